@@ -2,12 +2,14 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
 	"go1f/pkg/db"
+	"go1f/pkg/repeat"
 )
 
 // ResponseID представляет собой структуру для ответа с идентификатором задачи в формате JSON.
@@ -18,6 +20,8 @@ type ResponseID struct {
 // ResponseError представляет собой структуру для ответа с ошибкой в формате JSON.
 type ResponseError struct {
 	Error string `json:"error"`
+	// Details описывает ошибку в правиле повторения: код, место и подсказку.
+	Details *repeat.ParseError `json:"details,omitempty"`
 }
 
 // WriteJSON записывает данные в формате JSON в ответ HTTP.
@@ -34,6 +38,14 @@ func WriteError(w http.ResponseWriter, status int, errMsg string) {
 	WriteJSON(w, status, ResponseError{Error: errMsg})
 }
 
+// WriteErrorDetails записывает ошибку err в ответ HTTP. Если причина — ошибка разбора
+// правила повторения, её подробности добавляются в поле details.
+func WriteErrorDetails(w http.ResponseWriter, status int, errMsg string, err error) {
+	resp := ResponseError{Error: errMsg}
+	errors.As(err, &resp.Details)
+	WriteJSON(w, status, resp)
+}
+
 // AddTaskHandler обрабатывает HTTP запросы для добавления новой задачи в базу данных.
 func AddTaskHandler(w http.ResponseWriter, r *http.Request) {
 	task := db.Task{}
@@ -54,13 +66,30 @@ func AddTaskHandler(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusBadRequest, "Не указан заголовок задачи")
 		return
 	}
+	if task.Priority < 0 || task.Priority > db.MaxPriority {
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("Приоритет задачи должен быть числом от 0 до %d", db.MaxPriority))
+		return
+	}
+	if task.Tags, err = normalizeTags(task.Tags); err != nil {
+		WriteError(w, http.StatusBadRequest, "Некорректные метки задачи: "+err.Error())
+		return
+	}
+	if err := checkList(task.ListID); err != nil {
+		WriteError(w, http.StatusBadRequest, "Некорректный список задачи: "+err.Error())
+		return
+	}
 
-	// 3. Проверить корректность даты и обработать логику повторения.
-	err = checkDate(&task)
+	// 3. Проверить корректность даты и обработать логику повторения в часовом поясе запроса.
+	now, err := requestNow(r)
 	if err != nil {
 		WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	err = checkDate(&task, now)
+	if err != nil {
+		WriteErrorDetails(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
 
 	// 4. Вызвать функцию db.AddTask(task), чтобы добавить задачу в базу данных.
 	id, err := db.AddTask(&task)
@@ -74,8 +103,8 @@ func AddTaskHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // checkDate проверяет и корректирует дату задачи в соответствии с правилами.
-func checkDate(task *db.Task) error {
-	now := time.Now()
+// Сегодняшний день определяется по now, переданному в нужном часовом поясе.
+func checkDate(task *db.Task, now time.Time) error {
 	todayFormatted := now.Format("20060102")
 
 	// Если task.Date пустая строка, то присваиваем ему текущую дату.
@@ -83,34 +112,73 @@ func checkDate(task *db.Task) error {
 		task.Date = todayFormatted
 	}
 
-	// Проверяем, что в task.Date указана корректная дата.
-	t, err := time.Parse("20060102", task.Date)
+	// Относительную дату вроде «завтра» или «+3d» приводим к формату 20060102.
+	if date, ok := parseDateInput(task.Date, now); ok {
+		task.Date = date
+	}
+
+	// Проверяем, что в task.Date указана корректная дата, и приводим её к формату 20060102.
+	t, err := parseDate(task.Date)
 	if err != nil {
-		return fmt.Errorf("дата представлена в формате, отличном от 20060102: %w", err)
+		return fmt.Errorf("дата представлена в неподдерживаемом формате (%s): %w", dateInputHint(), err)
+	}
+	task.Date = t.Format("20060102")
+
+	// Если указано время задачи, проверяем его и приводим к виду 15:04.
+	if task.Time != "" {
+		tm, err := time.Parse("15:04", task.Time)
+		if err != nil {
+			return fmt.Errorf("время представлено в формате, отличном от 15:04: %w", err)
+		}
+		task.Time = tm.Format("15:04")
+	}
+
+	// Если правило повторения указано, разбираем его и сохраняем в каноническом виде.
+	var rule repeat.Rule
+	if task.Repeat != "" {
+		rule, err = repeat.Parse(task.Repeat)
+		if err != nil {
+			return fmt.Errorf("правило повторения указано в неправильном формате: %w", err)
+		}
+		if err := repeat.CheckRRULEStart(task.Repeat, rule, t); err != nil {
+			return fmt.Errorf("правило повторения указано в неправильном формате: %w", err)
+		}
+		task.Repeat = rule.String()
+		if err := checkTime(rule, task.Time); err != nil {
+			return err
+		}
 	}
 
 	// Если дата задачи меньше сегодняшнего числа.
 	if afterNow(now, t) {
 		// Если правило повторения не указано или равно пустой строке, подставляется сегодняшнее число.
-		if task.Repeat == "" {
+		if rule == nil {
 			task.Date = todayFormatted
 		} else {
 			// При указанном правиле повторения вычисляем следующую дату, которая будет больше сегодняшнего числа.
-			nextDateStr, err := NextDate(now, task.Date, task.Repeat)
+			except, err := taskExceptions(task.ID)
 			if err != nil {
-				return fmt.Errorf("правило повторения указано в неправильном формате: %w", err)
+				return fmt.Errorf("ошибка получения дат-исключений задачи: %w", err)
+			}
+			start, err := parseStart(task.Date, task.Time)
+			if err != nil {
+				return err
+			}
+			next, err := nextAfter(now, start, rule, except)
+			if errors.Is(err, errSeriesEnded) || errors.Is(err, errNoNextDate) {
+				return err
 			}
-			task.Date = nextDateStr
-		}
-	} else {
-		// Если дата задачи не меньше сегодняшнего числа, но repeat указан,
-		// все равно проверяем repeat на корректность.
-		if task.Repeat != "" {
-			_, err := NextDate(now, task.Date, task.Repeat)
 			if err != nil {
 				return fmt.Errorf("правило повторения указано в неправильном формате: %w", err)
 			}
+			task.Date = next.Format("20060102")
+			if task.Time != "" {
+				task.Time = next.Format("15:04")
+			}
 		}
+	} else if series, ok := rule.(repeat.Series); ok && !series.Until.IsZero() && t.After(series.Until) {
+		// Дата задачи должна попадать в серию повторений.
+		return fmt.Errorf("дата задачи позже даты окончания повторений %s", series.Until.Format("20060102"))
 	}
 	return nil
 }
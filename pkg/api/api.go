@@ -6,16 +6,36 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"go1f/pkg/db"
 )
 
 func Init() {
+	initTimezone()
+	initDateLayouts()
 	http.HandleFunc("/api/task", TaskHandler)
 	http.HandleFunc("/api/nextdate", HandleNextDate)
 	http.HandleFunc("/api/tasks", GetTasksHandler)
 	http.HandleFunc("/api/task/done", DoneHandler)
+	http.HandleFunc("/api/task/skip", SkipHandler)
+	http.HandleFunc("/api/task/exceptions", ExceptionsHandler)
+	http.HandleFunc("/api/task/dependencies", DependenciesHandler)
+	http.HandleFunc("/api/repeat/validate", ValidateRepeatHandler)
+	http.HandleFunc("/api/occurrences", OccurrencesHandler)
+	http.HandleFunc("/api/tags", TagsHandler)
+	http.HandleFunc("/api/lists", ListsHandler)
+	http.HandleFunc("/api/subtasks", SubtasksHandler)
+	http.HandleFunc("/api/subtasks/order", ReorderSubtasksHandler)
+	http.HandleFunc("/api/holidays", HolidaysHandler)
+	http.HandleFunc("/api/holidays/import", ImportHolidaysHandler)
+	http.HandleFunc("/api/trash", TrashHandler)
+	http.HandleFunc("/api/trash/restore", RestoreHandler)
+	http.HandleFunc("/api/trash/purge", PurgeHandler)
+	http.HandleFunc("/api/history", HistoryHandler)
+	initHolidays()
+	initTrash()
 	log.Println("Обработчики зарегистрированы.")
 }
 
@@ -56,7 +76,7 @@ func DeleteTaskHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("Задача с ID %s успешно удалена.\n", taskID)
+	log.Printf("Задача с ID %s перемещена в корзину.\n", taskID)
 	WriteJSON(w, http.StatusOK, struct{}{}) // Отправляем пустой JSON в ответе
 }
 
@@ -78,31 +98,46 @@ func DoneHandler(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusInternalServerError, "Ошибка получения задачи: "+err.Error())
 		return
 	}
-	if task.Repeat == "" {
-		// 2. Если задача не повторяется, просто удаляем её
-		err = db.DeleteTask(taskID)
+	now, err := requestNow(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Снимок задачи до переноса: в историю записывается выполненная дата, а не следующая.
+	completed := *task
+
+	// 2. Если задача повторяется, вычисляем следующую дату и правило с учётом окончания серии
+	finished := task.Repeat == ""
+	if !finished {
+		except, err := taskExceptions(taskID)
 		if err != nil {
-			log.Printf("Ошибка удаления задачи с ID %s: %v\n", taskID, err)
-			WriteError(w, http.StatusInternalServerError, "Ошибка удаления задачи: "+err.Error())
+			WriteError(w, http.StatusInternalServerError, "Ошибка получения дат-исключений задачи: "+err.Error())
 			return
 		}
-		log.Printf("Задача с ID %s успешно удалена (не повторяется).\n", taskID)
-	} else {
-		// 3. Если задача повторяется, вычисляем следующую дату и обновляем её
-		date, err := NextDate(time.Now(), task.Date, task.Repeat)
+		ok, err := reschedule(now, task, except, true)
 		if err != nil {
 			log.Printf("Ошибка при вычислении следующей даты для задачи ID %s: %v\n", taskID, err)
 			WriteError(w, http.StatusBadRequest, "Ошибка при вычислении следующей даты: "+err.Error())
 			return
 		}
-		// Обновляем дату задачи
-		task.Date = date
-		err = db.UpdateTask(task)
-		if err != nil {
-			log.Printf("Ошибка обновления задачи с ID %s: %v\n", taskID, err)
-			WriteError(w, http.StatusInternalServerError, "Ошибка обновления задачи: "+err.Error())
-			return
-		}
+		finished = !ok
+	}
+
+	// 3. Записываем выполнение в историю и, если задача не повторяется или серия повторений
+	// завершена, перемещаем её в корзину, а иначе переносим на следующую дату — в одной транзакции
+	next := task
+	if finished {
+		next = nil
+	}
+	err = db.CompleteTask(&completed, next, now)
+	if err != nil {
+		log.Printf("Ошибка завершения задачи с ID %s: %v\n", taskID, err)
+		WriteError(w, http.StatusInternalServerError, "Ошибка завершения задачи: "+err.Error())
+		return
+	}
+	if finished {
+		log.Printf("Задача с ID %s перемещена в корзину (повторений больше нет).\n", taskID)
+	} else {
 		log.Printf("Задача с ID %s успешно обновлена до следующей даты: %s\n", taskID, task.Date)
 	}
 
@@ -136,11 +171,28 @@ func UpdateTaskHandler(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusBadRequest, "Не указан заголовок задачи")
 		return
 	}
+	if task.Priority < 0 || task.Priority > db.MaxPriority {
+		WriteError(w, http.StatusBadRequest, fmt.Sprintf("Приоритет задачи должен быть числом от 0 до %d", db.MaxPriority))
+		return
+	}
+	if task.Tags, err = normalizeTags(task.Tags); err != nil {
+		WriteError(w, http.StatusBadRequest, "Некорректные метки задачи: "+err.Error())
+		return
+	}
+	if err := checkList(task.ListID); err != nil {
+		WriteError(w, http.StatusBadRequest, "Некорректный список задачи: "+err.Error())
+		return
+	}
 
-	// 3. Проверить корректность даты и обработать логику повторения.
-	err = checkDate(&task)
+	// 3. Проверить корректность даты и обработать логику повторения в часовом поясе запроса.
+	now, err := requestNow(r)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "Некорректная дата или повторение: "+err.Error())
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = checkDate(&task, now)
+	if err != nil {
+		WriteErrorDetails(w, http.StatusBadRequest, "Некорректная дата или повторение: "+err.Error(), err)
 		return
 	}
 
@@ -178,6 +230,13 @@ func GetTaskByIDHandler(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusNotFound, "Задача не найдена")
 		return
 	}
+	layout, err := outputLayout(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fillRepeat(task, requestLang(r))
+	task.Date = formatDate(task.Date, layout)
 	log.Printf("Задача с ID %s успешно получена: %+v\n", taskID, task)
 	WriteJSON(w, http.StatusOK, task)
 	log.Printf("Ответ отправлен для задачи с ID %s\n", taskID)
@@ -188,16 +247,27 @@ func HandleNextDate(w http.ResponseWriter, r *http.Request) {
 	queryNow := r.URL.Query().Get("now")
 	queryDate := r.URL.Query().Get("date")
 	queryRepeat := r.URL.Query().Get("repeat")
+	queryTime := r.URL.Query().Get("time")
+
+	layout, err := outputLayout(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	var now time.Time
-	var err error
 
 	if queryNow == "" {
-		now = time.Now() // Если параметр 'now' не указан, берем текущую дату
+		// Если параметр 'now' не указан, берем текущую дату в часовом поясе запроса
+		now, err = requestNow(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 	} else {
-		now, err = time.Parse("20060102", queryNow)
+		now, err = parseNow(queryNow)
 		if err != nil {
-			http.Error(w, "Некорректный формат даты в параметре 'now'. Ожидается 20060102.", http.StatusBadRequest)
+			http.Error(w, "Некорректный формат даты в параметре 'now'. Ожидается дата в одном из форматов "+strings.Join(dateLayouts, ", ")+", возможно со временем 15:04 через пробел.", http.StatusBadRequest)
 			return
 		}
 	}
@@ -212,11 +282,16 @@ func HandleNextDate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	nextDateStr, err := NextDate(now, queryDate, queryRepeat)
+	// Если указано время задачи, в ответе возвращаются дата и время через пробел.
+	nextDateStr, nextTimeStr, err := NextDateTime(now, queryDate, queryTime, queryRepeat)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	nextDateStr = formatDate(nextDateStr, layout)
+	if nextTimeStr != "" {
+		nextDateStr += " " + nextTimeStr
+	}
 
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(http.StatusOK)
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// dateLayouts — форматы дат, которые принимает API. Задаются через переменную окружения
+// TODO_DATE_LAYOUTS в виде списка layout'ов Go через запятую. Канонический формат 20060102,
+// в котором даты хранятся в БД, принимается всегда.
+var dateLayouts = []string{"20060102", "2006-01-02", "02.01.2006"}
+
+// dateFormats — именованные форматы дат для выдачи, которые можно выбрать параметром date_format.
+var dateFormats = map[string]string{
+	"compact": "20060102",
+	"iso":     "2006-01-02",
+	"ru":      "02.01.2006",
+}
+
+// initDateLayouts настраивает список принимаемых форматов дат.
+func initDateLayouts() {
+	value := os.Getenv("TODO_DATE_LAYOUTS")
+	if value == "" {
+		return
+	}
+	layouts := []string{"20060102"}
+	for _, layout := range strings.Split(value, ",") {
+		layout = strings.TrimSpace(layout)
+		if layout != "" && layout != "20060102" {
+			layouts = append(layouts, layout)
+		}
+	}
+	dateLayouts = layouts
+	log.Printf("Принимаемые форматы дат: %s\n", strings.Join(dateLayouts, ", "))
+}
+
+// parseDate разбирает дату в любом из принимаемых форматов.
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, s); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("дата %q не соответствует ни одному из форматов %s", s, strings.Join(dateLayouts, ", "))
+}
+
+// outputLayout возвращает формат дат для ответа. Он выбирается параметром date_format
+// или заголовком X-Date-Format: имя из dateFormats или один из принимаемых форматов.
+// По умолчанию используется канонический формат 20060102.
+func outputLayout(r *http.Request) (string, error) {
+	name := r.URL.Query().Get("date_format")
+	if name == "" {
+		name = r.Header.Get("X-Date-Format")
+	}
+	if name == "" {
+		return "20060102", nil
+	}
+	if layout, ok := dateFormats[name]; ok {
+		return layout, nil
+	}
+	for _, layout := range dateLayouts {
+		if layout == name {
+			return layout, nil
+		}
+	}
+	return "", fmt.Errorf("неизвестный формат дат %q", name)
+}
+
+// formatDate переводит дату из канонического формата 20060102 в формат layout.
+// Если дату разобрать не удалось, она возвращается без изменений.
+func formatDate(date string, layout string) string {
+	t, err := time.Parse("20060102", date)
+	if err != nil {
+		return date
+	}
+	return t.Format(layout)
+}
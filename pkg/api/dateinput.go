@@ -0,0 +1,75 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateInputHint перечисляет поддерживаемые формы даты для сообщений об ошибках.
+// Форматы дат берутся из dateLayouts, поэтому учитывают TODO_DATE_LAYOUTS.
+func dateInputHint() string {
+	return "поддерживаются даты в форматах " + strings.Join(dateLayouts, ", ") + ", а также today, tomorrow, " +
+		"\"day after tomorrow\", +3d, +2w, \"in 3 days\", \"next monday\", сегодня, завтра, " +
+		"послезавтра, \"через 3 дня\", \"через неделю\", \"в следующий понедельник\""
+}
+
+// weekdayNames сопоставляет названия дней недели (в том числе в винительном падеже) с днём недели.
+var weekdayNames = map[string]time.Weekday{
+	"monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday, "sunday": time.Sunday,
+	"понедельник": time.Monday, "вторник": time.Tuesday, "среда": time.Wednesday, "среду": time.Wednesday,
+	"четверг": time.Thursday, "пятница": time.Friday, "пятницу": time.Friday,
+	"суббота": time.Saturday, "субботу": time.Saturday, "воскресенье": time.Sunday,
+}
+
+var (
+	// shiftRe — относительный сдвиг: "+3d", "+2w", "in 3 days", "через 3 дня", "через неделю".
+	shiftRe = regexp.MustCompile(`^(?:\+(\d+)\s*([dw])|in (\d+) (days?|weeks?)|через (\d+)? ?(дня|дней|день|недел[юиь]))$`)
+	// weekdayRe — день недели: "next monday", "monday", "в следующий понедельник", "в пятницу".
+	weekdayRe = regexp.MustCompile(`^(?:next |в |в следующ(?:ий|ую|ее) |следующ(?:ий|ая|ее) )?(\p{L}+)$`)
+)
+
+// parseDateInput преобразует относительную дату на английском или русском языке
+// («tomorrow», «+3d», «next monday», «завтра», «через 3 дня») в формат 20060102.
+// Дата отсчитывается от now. Если строку разобрать не удалось, возвращает false.
+func parseDateInput(s string, now time.Time) (string, bool) {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch s {
+	case "today", "сегодня":
+		return today.Format("20060102"), true
+	case "tomorrow", "завтра":
+		return today.AddDate(0, 0, 1).Format("20060102"), true
+	case "day after tomorrow", "послезавтра":
+		return today.AddDate(0, 0, 2).Format("20060102"), true
+	}
+
+	if m := shiftRe.FindStringSubmatch(s); m != nil {
+		number, unit := m[1]+m[3]+m[5], m[2]+m[4]+m[6]
+		n := 1
+		if number != "" {
+			n, _ = strconv.Atoi(number)
+		}
+		if strings.HasPrefix(unit, "w") || strings.HasPrefix(unit, "недел") {
+			n *= 7
+		}
+		if n > 0 && n <= 3660 {
+			return today.AddDate(0, 0, n).Format("20060102"), true
+		}
+		return "", false
+	}
+
+	if m := weekdayRe.FindStringSubmatch(s); m != nil {
+		weekday, ok := weekdayNames[m[1]]
+		if !ok {
+			return "", false
+		}
+		// Ближайший такой день недели после сегодняшнего.
+		days := (int(weekday)-int(today.Weekday())+6)%7 + 1
+		return today.AddDate(0, 0, days).Format("20060102"), true
+	}
+	return "", false
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"go1f/pkg/db"
+)
+
+// DependenciesResp представляет собой структуру для ответа с зависимостями задачи в формате JSON.
+type DependenciesResp struct {
+	// BlockedBy — ID задач, которые нужно выполнить до этой задачи.
+	BlockedBy []string `json:"blocked_by"`
+	// Blocks — ID задач, которые ждут выполнения этой задачи.
+	Blocks []string `json:"blocks"`
+}
+
+// DependenciesHandler обрабатывает HTTP запросы для управления зависимостями задачи.
+// Задача id заблокирована задачей blocker, пока blocker не будет выполнена.
+func DependenciesHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Получен запрос по пути: %s, метод: %s\n", r.URL.Path, r.Method)
+	taskID := r.URL.Query().Get("id")
+	if taskID == "" {
+		WriteError(w, http.StatusBadRequest, "Не указан ID задачи")
+		return
+	}
+	if _, err := db.GetTask(taskID); err != nil {
+		WriteError(w, http.StatusNotFound, "Задача не найдена")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		blockedBy, blocks, err := db.Dependencies(taskID)
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка получения зависимостей задачи: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, DependenciesResp{BlockedBy: blockedBy, Blocks: blocks})
+	case http.MethodPost:
+		blockerID := r.URL.Query().Get("blocker")
+		if _, err := db.GetTask(blockerID); err != nil {
+			WriteError(w, http.StatusNotFound, "Блокирующая задача не найдена")
+			return
+		}
+		err := db.AddDependency(taskID, blockerID)
+		if errors.Is(err, db.ErrDependencyCycle) {
+			WriteError(w, http.StatusBadRequest, "Ошибка добавления зависимости: "+err.Error())
+			return
+		}
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка добавления зависимости: "+err.Error())
+			return
+		}
+		log.Printf("Задача с ID %s заблокирована задачей с ID %s\n", taskID, blockerID)
+		WriteJSON(w, http.StatusOK, struct{}{})
+	case http.MethodDelete:
+		blockerID := r.URL.Query().Get("blocker")
+		if _, err := db.GetTask(blockerID); err != nil {
+			WriteError(w, http.StatusNotFound, "Блокирующая задача не найдена")
+			return
+		}
+		if err := db.DeleteDependency(taskID, blockerID); err != nil {
+			WriteError(w, http.StatusNotFound, "Ошибка удаления зависимости: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, struct{}{})
+	default:
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+	}
+}
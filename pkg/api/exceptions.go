@@ -0,0 +1,154 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"go1f/pkg/db"
+	"go1f/pkg/repeat"
+)
+
+// ExceptionsResp представляет собой структуру для ответа с датами-исключениями задачи в формате JSON.
+type ExceptionsResp struct {
+	Dates []string `json:"dates"`
+}
+
+// ExceptionsHandler обрабатывает HTTP запросы для управления датами-исключениями задачи.
+// Дата-исключение — дата, в которую повторение задачи пропускается.
+func ExceptionsHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Получен запрос по пути: %s, метод: %s\n", r.URL.Path, r.Method)
+	taskID := r.URL.Query().Get("id")
+	if taskID == "" {
+		WriteError(w, http.StatusBadRequest, "Не указан ID задачи")
+		return
+	}
+	if _, err := db.GetTask(taskID); err != nil {
+		WriteError(w, http.StatusNotFound, "Задача не найдена")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		layout, err := outputLayout(r)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dates, err := db.Exceptions(taskID)
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка получения дат-исключений: "+err.Error())
+			return
+		}
+		for i, date := range dates {
+			dates[i] = formatDate(date, layout)
+		}
+		WriteJSON(w, http.StatusOK, ExceptionsResp{Dates: dates})
+	case http.MethodPost:
+		date, err := exceptionDate(r)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := db.AddException(taskID, date); err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка добавления даты-исключения: "+err.Error())
+			return
+		}
+		log.Printf("Для задачи с ID %s добавлена дата-исключение %s\n", taskID, date)
+		WriteJSON(w, http.StatusOK, struct{}{})
+	case http.MethodDelete:
+		date, err := exceptionDate(r)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := db.DeleteException(taskID, date); err != nil {
+			WriteError(w, http.StatusNotFound, "Ошибка удаления даты-исключения: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, struct{}{})
+	}
+}
+
+// exceptionDate возвращает дату-исключение из параметра date запроса в формате 20060102.
+// Дата принимается в любом из поддерживаемых форматов.
+func exceptionDate(r *http.Request) (string, error) {
+	date, err := parseDate(r.URL.Query().Get("date"))
+	if err != nil {
+		return "", fmt.Errorf("некорректная дата-исключение: %w", err)
+	}
+	return date.Format("20060102"), nil
+}
+
+// SkipHandler обрабатывает HTTP запросы для пропуска ближайшего повторения задачи.
+// Текущая дата задачи добавляется в даты-исключения, а задача переносится на следующую дату
+// без отметки о выполнении: оставшееся число повторений серии не уменьшается.
+// Для правил h и min дата-исключение исключила бы весь день, поэтому задача просто
+// переносится на следующее повторение.
+func SkipHandler(w http.ResponseWriter, r *http.Request) {
+	taskID := r.URL.Query().Get("id")
+	log.Printf("Получен запрос на пропуск повторения задачи с ID: %s\n", taskID)
+
+	if taskID == "" {
+		WriteError(w, http.StatusBadRequest, "Не указан ID задачи")
+		return
+	}
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+		return
+	}
+
+	task, err := db.GetTask(taskID)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, "Задача не найдена")
+		return
+	}
+	if task.Repeat == "" {
+		WriteError(w, http.StatusBadRequest, "Задача не повторяется, пропустить повторение нельзя")
+		return
+	}
+
+	now, err := requestNow(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rule, err := repeat.Parse(task.Repeat)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Правило повторения указано в неправильном формате: "+err.Error())
+		return
+	}
+	except, err := taskExceptions(taskID)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Ошибка получения дат-исключений задачи: "+err.Error())
+		return
+	}
+	skipped := ""
+	if _, subDaily := repeat.Step(rule); !subDaily {
+		skipped = task.Date
+		except[skipped] = true
+	}
+	ok, err := reschedule(now, task, except, false)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Ошибка при вычислении следующей даты: "+err.Error())
+		return
+	}
+
+	// Дата-исключение сохраняется вместе с переносом задачи, в одной транзакции.
+	next := task
+	if !ok {
+		// Пропущенное повторение было последним в серии — задача завершена.
+		next = nil
+	}
+	if err := db.SkipTask(taskID, skipped, next); err != nil {
+		WriteError(w, http.StatusInternalServerError, "Ошибка пропуска повторения задачи: "+err.Error())
+		return
+	}
+	if !ok {
+		log.Printf("Задача с ID %s перемещена в корзину: пропущено последнее повторение.\n", taskID)
+		WriteJSON(w, http.StatusOK, struct{}{})
+		return
+	}
+	log.Printf("Повторение задачи с ID %s пропущено, следующая дата: %s\n", taskID, task.Date)
+	WriteJSON(w, http.StatusOK, struct{}{})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go1f/pkg/db"
+)
+
+// historyLimit — максимальное количество записей в ответе истории выполнения.
+const historyLimit = 100
+
+// HistoryResp представляет собой структуру для ответа с историей выполнения задач в формате JSON.
+type HistoryResp struct {
+	Completions []*db.Completion `json:"completions"`
+}
+
+// HistoryHandler обрабатывает HTTP запросы для получения истории выполнения задач.
+// Параметры from и to ограничивают даты выполнения включительно и принимаются в любом
+// из поддерживаемых форматов дат; границы суток берутся в часовом поясе запроса.
+// Параметр task_id оставляет только выполнения одной задачи.
+func HistoryHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Получен запрос по пути: %s, метод: %s\n", r.URL.Path, r.Method)
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+		return
+	}
+	loc, err := requestLocation(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	layout, err := outputLayout(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := db.HistoryFilter{TaskID: r.URL.Query().Get("task_id")}
+	if filter.TaskID != "" {
+		if _, err := strconv.ParseInt(filter.TaskID, 10, 64); err != nil {
+			WriteError(w, http.StatusBadRequest, fmt.Sprintf("Некорректный ID задачи %q", filter.TaskID))
+			return
+		}
+	}
+	if from := r.URL.Query().Get("from"); from != "" {
+		date, err := parseDate(from)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "Некорректная дата from: "+err.Error())
+			return
+		}
+		filter.From = dayStart(date, loc)
+	}
+	if to := r.URL.Query().Get("to"); to != "" {
+		date, err := parseDate(to)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "Некорректная дата to: "+err.Error())
+			return
+		}
+		filter.To = dayStart(date, loc).AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		WriteError(w, http.StatusBadRequest, "Дата from не может быть позже даты to")
+		return
+	}
+
+	completions, err := db.History(historyLimit, filter)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Ошибка получения истории выполнения: "+err.Error())
+		return
+	}
+	for _, c := range completions {
+		c.Date = formatDate(c.Date, layout)
+		if doneAt, err := time.Parse(time.RFC3339, c.DoneAt); err == nil {
+			c.DoneAt = doneAt.In(loc).Format(time.RFC3339)
+		}
+	}
+	WriteJSON(w, http.StatusOK, HistoryResp{Completions: completions})
+}
+
+// dayStart возвращает начало суток date в часовом поясе loc.
+func dayStart(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
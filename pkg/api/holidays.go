@@ -0,0 +1,183 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"go1f/pkg/db"
+	"go1f/pkg/repeat"
+)
+
+// HolidaysResp представляет собой структуру для ответа со списком праздничных дней в формате JSON.
+type HolidaysResp struct {
+	Holidays []*db.Holiday `json:"holidays"`
+}
+
+// ImportResp представляет собой структуру для ответа с количеством импортированных записей.
+type ImportResp struct {
+	Imported int `json:"imported"`
+}
+
+// holidayCalendar хранит в памяти праздничные дни из БД, чтобы правила рабочих дней
+// не обращались к БД при проверке каждой даты.
+type holidayCalendar struct {
+	mu    sync.RWMutex
+	dates map[string]bool
+}
+
+// holidays — календарь праздников, который использует движок правил повторения.
+var holidays = &holidayCalendar{dates: map[string]bool{}}
+
+// IsHoliday возвращает true, если date есть в календаре праздников.
+func (c *holidayCalendar) IsHoliday(date time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dates[date.Format("20060102")]
+}
+
+// reload перечитывает праздничные дни из БД.
+func (c *holidayCalendar) reload() error {
+	list, err := db.Holidays()
+	if err != nil {
+		return err
+	}
+	dates := make(map[string]bool, len(list))
+	for _, holiday := range list {
+		dates[holiday.Date] = true
+	}
+	c.mu.Lock()
+	c.dates = dates
+	c.mu.Unlock()
+	return nil
+}
+
+// initHolidays загружает календарь праздников и подключает его к движку правил повторения.
+func initHolidays() {
+	if err := holidays.reload(); err != nil {
+		log.Printf("Ошибка загрузки праздничных дней: %v\n", err)
+	}
+	repeat.Holidays = holidays
+}
+
+// HolidaysHandler обрабатывает HTTP запросы для управления праздничными днями.
+func HolidaysHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Получен запрос по пути: %s, метод: %s\n", r.URL.Path, r.Method)
+	switch r.Method {
+	case http.MethodGet:
+		GetHolidaysHandler(w, r)
+	case http.MethodPost:
+		AddHolidayHandler(w, r)
+	case http.MethodDelete:
+		DeleteHolidayHandler(w, r)
+	}
+}
+
+// GetHolidaysHandler обрабатывает HTTP запросы для получения списка праздничных дней.
+func GetHolidaysHandler(w http.ResponseWriter, r *http.Request) {
+	layout, err := outputLayout(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	list, err := db.Holidays()
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Ошибка получения праздничных дней: "+err.Error())
+		return
+	}
+	for _, holiday := range list {
+		holiday.Date = formatDate(holiday.Date, layout)
+	}
+	WriteJSON(w, http.StatusOK, HolidaysResp{Holidays: list})
+}
+
+// AddHolidayHandler обрабатывает HTTP запросы для добавления праздничного дня.
+func AddHolidayHandler(w http.ResponseWriter, r *http.Request) {
+	var holiday db.Holiday
+	if err := readJSON(r, &holiday); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	saveHolidays(w, []*db.Holiday{&holiday})
+}
+
+// ImportHolidaysHandler обрабатывает HTTP запросы для массового импорта праздничных дней.
+// Тело запроса имеет тот же формат, что и ответ GET /api/holidays.
+func ImportHolidaysHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+		return
+	}
+	var req HolidaysResp
+	if err := readJSON(r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Holidays) == 0 {
+		WriteError(w, http.StatusBadRequest, "Не указаны праздничные дни")
+		return
+	}
+	saveHolidays(w, req.Holidays)
+}
+
+// saveHolidays проверяет и сохраняет праздничные дни, после чего обновляет календарь.
+// Даты принимаются в любом из поддерживаемых форматов и сохраняются в формате 20060102.
+func saveHolidays(w http.ResponseWriter, list []*db.Holiday) {
+	for _, holiday := range list {
+		date, err := parseDate(holiday.Date)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "Некорректная дата праздничного дня: "+err.Error())
+			return
+		}
+		holiday.Date = date.Format("20060102")
+	}
+	if err := db.AddHolidays(list); err != nil {
+		WriteError(w, http.StatusInternalServerError, "Ошибка сохранения праздничных дней: "+err.Error())
+		return
+	}
+	if err := holidays.reload(); err != nil {
+		WriteError(w, http.StatusInternalServerError, "Ошибка обновления календаря праздников: "+err.Error())
+		return
+	}
+	log.Printf("Сохранено праздничных дней: %d\n", len(list))
+	WriteJSON(w, http.StatusOK, ImportResp{Imported: len(list)})
+}
+
+// DeleteHolidayHandler обрабатывает HTTP запросы для удаления праздничного дня по дате.
+func DeleteHolidayHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("date")
+	if query == "" {
+		WriteError(w, http.StatusBadRequest, "Не указана дата праздничного дня")
+		return
+	}
+	date, err := parseDate(query)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Некорректная дата праздничного дня: "+err.Error())
+		return
+	}
+	if err := db.DeleteHoliday(date.Format("20060102")); err != nil {
+		WriteError(w, http.StatusNotFound, "Ошибка удаления праздничного дня: "+err.Error())
+		return
+	}
+	if err := holidays.reload(); err != nil {
+		WriteError(w, http.StatusInternalServerError, "Ошибка обновления календаря праздников: "+err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, struct{}{})
+}
+
+// readJSON читает тело запроса и десериализует его из JSON в v.
+func readJSON(r *http.Request, v any) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения тела запроса: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("ошибка десериализации JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"go1f/pkg/db"
+)
+
+// ListsResp представляет собой структуру для ответа со списками задач в формате JSON.
+type ListsResp struct {
+	Lists []*db.List `json:"lists"`
+}
+
+// ListsHandler обрабатывает HTTP запросы для работы со списками задач.
+func ListsHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Получен запрос по пути: %s, метод: %s\n", r.URL.Path, r.Method)
+	switch r.Method {
+	case http.MethodGet:
+		list, err := db.Lists()
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка получения списков задач: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, ListsResp{Lists: list})
+	case http.MethodPost:
+		var list db.List
+		if err := readList(r, &list); err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		id, err := db.AddList(list.Name)
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка добавления списка задач: "+err.Error())
+			return
+		}
+		log.Printf("Добавлен список задач %q с ID %d\n", list.Name, id)
+		WriteJSON(w, http.StatusOK, ResponseID{ID: fmt.Sprintf("%d", id)})
+	case http.MethodPut:
+		var list db.List
+		if err := readList(r, &list); err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if list.ID == "" {
+			WriteError(w, http.StatusBadRequest, "Не указан ID списка задач")
+			return
+		}
+		if err := db.UpdateList(&list); err != nil {
+			WriteError(w, http.StatusNotFound, "Ошибка обновления списка задач: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, struct{}{})
+	case http.MethodDelete:
+		DeleteListHandler(w, r)
+	default:
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+	}
+}
+
+// DeleteListHandler обрабатывает HTTP запросы для удаления списка задач.
+// Параметр mode задаёт судьбу задач списка: move (по умолчанию) переносит их во «Входящие»,
+// cascade удаляет вместе со списком.
+func DeleteListHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		WriteError(w, http.StatusBadRequest, "Не указан ID списка задач")
+		return
+	}
+	if id == db.InboxID {
+		WriteError(w, http.StatusBadRequest, "Список «Входящие» нельзя удалить")
+		return
+	}
+	var cascade bool
+	switch mode := r.URL.Query().Get("mode"); mode {
+	case "", "move":
+	case "cascade":
+		cascade = true
+	default:
+		WriteError(w, http.StatusBadRequest, "Параметр 'mode' должен быть move или cascade")
+		return
+	}
+	if err := db.DeleteList(id, cascade); err != nil {
+		WriteError(w, http.StatusNotFound, "Ошибка удаления списка задач: "+err.Error())
+		return
+	}
+	log.Printf("Список задач с ID %s удалён (cascade: %t)\n", id, cascade)
+	WriteJSON(w, http.StatusOK, struct{}{})
+}
+
+// readList читает список задач из тела запроса и проверяет его название.
+func readList(r *http.Request, list *db.List) error {
+	if err := readJSON(r, list); err != nil {
+		return err
+	}
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Name == "" {
+		return fmt.Errorf("не указано название списка задач")
+	}
+	return nil
+}
+
+// checkList проверяет, что список задач с ID id существует. Пустой ID и InboxID
+// означают «Входящие» и всегда допустимы.
+func checkList(id string) error {
+	if id == "" || id == db.InboxID {
+		return nil
+	}
+	if _, err := db.GetList(id); err != nil {
+		return err
+	}
+	return nil
+}
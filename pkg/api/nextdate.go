@@ -3,9 +3,11 @@ package api
 import (
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
+
+	"go1f/pkg/db"
+	"go1f/pkg/repeat"
 )
 
 // afterNow возвращает true, если date больше now.
@@ -15,52 +17,182 @@ func afterNow(date, now time.Time) bool {
 		(date.Year() == now.Year() && date.Month() == now.Month() && date.Day() > now.Day())
 }
 
+// errNoNextDate возвращается, если по правилу повторения не удалось найти ни одной даты
+// в пределах, в которых ведётся поиск.
+var errNoNextDate = errors.New("не найдено ни одной подходящей даты")
+
+// errSeriesEnded возвращается, если следующее повторение приходится на дату позже
+// даты окончания серии (модификатор until).
+var errSeriesEnded = errors.New("серия повторений завершилась")
+
+// seriesEnded возвращает true, если правило rule не даёт даты после after только потому,
+// что она приходится на дату позже окончания серии.
+func seriesEnded(rule repeat.Rule, after time.Time) bool {
+	series, ok := rule.(repeat.Series)
+	return ok && !series.Until.IsZero() && !series.Rule.Next(after).IsZero()
+}
+
+// nextAfter возвращает первую дату повторения по правилу rule, отсчитанную от start, которая больше now.
+// Для правил h и min сравнивается не только дата, но и время. Даты из except (в формате 20060102) пропускаются.
+func nextAfter(now, start time.Time, rule repeat.Rule, except map[string]bool) (time.Time, error) {
+	// Даты задач хранятся без часового пояса, поэтому сравниваем их с показаниями часов now.
+	wall := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, time.UTC)
+	step, subDaily := repeat.Step(rule)
+
+	date := start
+	if subDaily && date.Before(wall) {
+		// Чтобы не перебирать все повторения с давних пор, сразу переходим к последнему перед now.
+		date = date.Add(wall.Sub(date) / step * step)
+	}
+	for {
+		prev := date
+		date = rule.Next(date)
+		if date.IsZero() {
+			if seriesEnded(rule, prev) {
+				return time.Time{}, fmt.Errorf("%w: %s", errSeriesEnded, rule)
+			}
+			return time.Time{}, fmt.Errorf("%w для правила %s", errNoNextDate, rule)
+		}
+		passed := afterNow(date, now)
+		if subDaily {
+			passed = date.After(wall)
+		}
+		if passed && !except[date.Format("20060102")] {
+			return date, nil
+		}
+	}
+}
+
+// parseStart разбирает дату задачи и её необязательное время в момент, от которого отсчитываются повторения.
+func parseStart(dstart string, dtime string) (time.Time, error) {
+	startDate, err := parseDate(dstart)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("время в переменной dstart не может быть преобразовано в корректную дату: %w", err)
+	}
+	if dtime == "" {
+		return startDate, nil
+	}
+	tm, err := time.Parse("15:04", dtime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("время представлено в формате, отличном от 15:04: %w", err)
+	}
+	return startDate.Add(time.Duration(tm.Hour())*time.Hour + time.Duration(tm.Minute())*time.Minute), nil
+}
+
+// parseNow разбирает значение параметра now: дату в одном из принимаемых форматов,
+// возможно со временем через пробел, например "20060102 15:04".
+func parseNow(s string) (time.Time, error) {
+	date, tm, _ := strings.Cut(s, " ")
+	return parseStart(date, tm)
+}
+
+// checkTime проверяет, что для правил h и min указано время задачи.
+func checkTime(rule repeat.Rule, dtime string) error {
+	if _, subDaily := repeat.Step(rule); subDaily && dtime == "" {
+		return fmt.Errorf("для правила %s необходимо указать время задачи", rule)
+	}
+	return nil
+}
+
 // NextDate вычисляет следующую дату повторения задачи.
-func NextDate(now time.Time, dstart string, repeat string) (string, error) {
-	if repeat == "" {
-		return "", errors.New("в параметре repeat — пустая строка")
+func NextDate(now time.Time, dstart string, rule string) (string, error) {
+	date, _, err := NextDateTime(now, dstart, "", rule)
+	return date, err
+}
+
+// NextDateTime вычисляет следующую дату и время повторения задачи со временем dtime.
+// Время может быть пустым для всех правил, кроме h и min; тогда пустым возвращается и время.
+func NextDateTime(now time.Time, dstart string, dtime string, rule string) (string, string, error) {
+	if rule == "" {
+		return "", "", errors.New("в параметре repeat — пустая строка")
+	}
+
+	start, err := parseStart(dstart, dtime)
+	if err != nil {
+		return "", "", err
 	}
 
-	startDate, err := time.Parse("20060102", dstart)
+	parsed, err := repeat.Parse(rule)
 	if err != nil {
-		return "", fmt.Errorf("время в переменной dstart не может быть преобразовано в корректную дату: %w", err)
+		return "", "", err
+	}
+	if err := checkTime(parsed, dtime); err != nil {
+		return "", "", err
 	}
 
-	parts := strings.Split(repeat, " ")
-	currentDate := startDate
+	next, err := nextAfter(now, start, parsed, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if dtime == "" {
+		return next.Format("20060102"), "", nil
+	}
+	return next.Format("20060102"), next.Format("15:04"), nil
+}
 
-	switch parts[0] {
-	case "d":
-		if len(parts) != 2 {
-			return "", errors.New("d: не указан интервал в днях")
-		}
-		intervalDays, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return "", errors.New("d: интервал в днях должен быть числом")
-		}
-		if intervalDays <= 0 || intervalDays > 400 {
-			return "", fmt.Errorf("d %d: превышен максимально допустимый интервал (1-400)", intervalDays)
-		}
+// reschedule переносит задачу на дату (и время) после текущего повторения, пропуская
+// даты из except, и обновляет её правило повторения. Если complete == true, текущее
+// повторение считается выполненным и уменьшает оставшееся число повторений серии;
+// иначе оно просто пропускается. Для правил с модификатором after следующая дата
+// отсчитывается не от даты задачи, а от now.
+// Возвращает false, если серия повторений завершилась (по until или count); задача в этом
+// случае не меняется. Если дату не удалось найти по другой причине, возвращается ошибка.
+func reschedule(now time.Time, task *db.Task, except map[string]bool, complete bool) (bool, error) {
+	start, err := parseStart(task.Date, task.Time)
+	if err != nil {
+		return false, err
+	}
+	parsed, err := repeat.Parse(task.Repeat)
+	if err != nil {
+		return false, err
+	}
+	if err := checkTime(parsed, task.Time); err != nil {
+		return false, err
+	}
 
-		for {
-			currentDate = currentDate.AddDate(0, 0, intervalDays)
-			if afterNow(currentDate, now) {
-				break
-			}
-		}
-	case "y":
-		if len(parts) != 1 {
-			return "", errors.New("y: этот параметр не требует дополнительных уточнений")
+	if repeat.AfterDone(parsed) {
+		if _, subDaily := repeat.Step(parsed); subDaily {
+			start = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, time.UTC)
+		} else {
+			start = time.Date(now.Year(), now.Month(), now.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
 		}
-		for {
-			currentDate = currentDate.AddDate(1, 0, 0)
-			if afterNow(currentDate, now) {
-				break
-			}
+	}
+	if complete {
+		var ok bool
+		parsed, ok = repeat.Complete(parsed)
+		if !ok {
+			return false, nil
 		}
-	default:
-		return "", fmt.Errorf("указан неверный формат repeat: %s", repeat)
+	}
+	next, err := nextAfter(now, start, parsed, except)
+	if errors.Is(err, errSeriesEnded) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
 	}
 
-	return currentDate.Format("20060102"), nil
+	task.Date = next.Format("20060102")
+	if task.Time != "" {
+		task.Time = next.Format("15:04")
+	}
+	task.Repeat = parsed.String()
+	return true, nil
+}
+
+// taskExceptions возвращает даты-исключения задачи в виде множества.
+// Для новой задачи без ID возвращается пустое множество.
+func taskExceptions(id string) (map[string]bool, error) {
+	except := map[string]bool{}
+	if id == "" {
+		return except, nil
+	}
+	dates, err := db.Exceptions(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, date := range dates {
+		except[date] = true
+	}
+	return except, nil
 }
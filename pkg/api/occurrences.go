@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go1f/pkg/repeat"
+)
+
+const (
+	// defaultOccurrences — количество дат, возвращаемое, если параметр count не указан.
+	defaultOccurrences = 5
+	// maxOccurrences — максимальное количество дат в одном ответе.
+	maxOccurrences = 50
+)
+
+// Occurrences возвращает до count ближайших дат повторения по правилу rule, отсчитанных от start,
+// которые больше now, пропуская даты из except. Если серия повторений завершилась,
+// возвращается пустой список. Первая дата совпадает с результатом NextDate, каждая следующая —
+// с тем, что вернёт NextDate после отметки о выполнении предыдущей.
+func Occurrences(now time.Time, start time.Time, rule repeat.Rule, except map[string]bool, count int) ([]time.Time, error) {
+	// Для серии с ограниченным числом повторений показываем только оставшиеся.
+	if series, ok := rule.(repeat.Series); ok && series.Count > 0 && series.Count < count {
+		count = series.Count
+	}
+	dates := make([]time.Time, 0, count)
+	date := start
+	for len(dates) < count {
+		var err error
+		date, err = nextAfter(now, date, rule, except)
+		if errors.Is(err, errSeriesEnded) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// OccurrencesHandler обрабатывает HTTP запросы для предпросмотра ближайших дат повторения задачи.
+func OccurrencesHandler(w http.ResponseWriter, r *http.Request) {
+	queryNow := r.URL.Query().Get("now")
+	queryDate := r.URL.Query().Get("date")
+	queryRepeat := r.URL.Query().Get("repeat")
+	queryCount := r.URL.Query().Get("count")
+	queryTime := r.URL.Query().Get("time")
+
+	now, err := requestNow(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if queryNow != "" {
+		now, err = parseNow(queryNow)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "Некорректный формат даты в параметре 'now'. Ожидается дата в одном из форматов "+strings.Join(dateLayouts, ", ")+", возможно со временем 15:04 через пробел.")
+			return
+		}
+	}
+
+	if queryDate == "" {
+		WriteError(w, http.StatusBadRequest, "Параметр 'date' обязателен.")
+		return
+	}
+	start, err := parseStart(queryDate, queryTime)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if queryRepeat == "" {
+		WriteError(w, http.StatusBadRequest, "Параметр 'repeat' обязателен.")
+		return
+	}
+	rule, err := repeat.Parse(queryRepeat)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := checkTime(rule, queryTime); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	count := defaultOccurrences
+	if queryCount != "" {
+		count, err = strconv.Atoi(queryCount)
+		if err != nil || count <= 0 {
+			WriteError(w, http.StatusBadRequest, "Параметр 'count' должен быть положительным числом.")
+			return
+		}
+	}
+	if count > maxOccurrences {
+		count = maxOccurrences
+	}
+
+	// Если указан ID задачи, учитываем её даты-исключения, как это сделает DoneHandler.
+	except, err := taskExceptions(r.URL.Query().Get("id"))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "Ошибка получения дат-исключений задачи: "+err.Error())
+		return
+	}
+
+	dates, err := Occurrences(now, start, rule, except, count)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Если указано время задачи, каждое повторение возвращается как дата и время через пробел.
+	layout, err := outputLayout(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if queryTime != "" {
+		layout += " 15:04"
+	}
+	result := make([]string, len(dates))
+	for i, date := range dates {
+		result[i] = date.Format(layout)
+	}
+	WriteJSON(w, http.StatusOK, result)
+}
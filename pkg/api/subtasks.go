@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"go1f/pkg/db"
+)
+
+// SubtasksResp представляет собой структуру для ответа с чек-листом задачи в формате JSON.
+type SubtasksResp struct {
+	Subtasks []*db.Subtask `json:"subtasks"`
+}
+
+// ReorderReq представляет собой запрос на изменение порядка пунктов чек-листа.
+type ReorderReq struct {
+	TaskID string   `json:"task_id"`
+	IDs    []string `json:"ids"`
+}
+
+// SubtasksHandler обрабатывает HTTP запросы для работы с чек-листом задачи:
+// GET ?task_id= возвращает пункты, POST добавляет пункт в конец чек-листа,
+// PUT изменяет название и отметку о выполнении, DELETE ?id= удаляет пункт.
+func SubtasksHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Получен запрос по пути: %s, метод: %s\n", r.URL.Path, r.Method)
+	switch r.Method {
+	case http.MethodGet:
+		taskID := r.URL.Query().Get("task_id")
+		if _, err := db.GetTask(taskID); err != nil {
+			WriteError(w, http.StatusNotFound, "Задача не найдена")
+			return
+		}
+		list, err := db.Subtasks(taskID)
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка получения чек-листа: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, SubtasksResp{Subtasks: list})
+	case http.MethodPost:
+		var subtask db.Subtask
+		if err := readSubtask(r, &subtask); err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, err := db.GetTask(subtask.TaskID); err != nil {
+			WriteError(w, http.StatusNotFound, "Задача не найдена")
+			return
+		}
+		id, err := db.AddSubtask(&subtask)
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка добавления пункта чек-листа: "+err.Error())
+			return
+		}
+		log.Printf("В чек-лист задачи с ID %s добавлен пункт с ID %d\n", subtask.TaskID, id)
+		WriteJSON(w, http.StatusOK, ResponseID{ID: subtask.ID})
+	case http.MethodPut:
+		var subtask db.Subtask
+		if err := readSubtask(r, &subtask); err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if subtask.ID == "" {
+			WriteError(w, http.StatusBadRequest, "Не указан ID пункта чек-листа")
+			return
+		}
+		if err := checkSubtaskTask(subtask.ID); err != nil {
+			WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err := db.UpdateSubtask(&subtask); err != nil {
+			WriteError(w, http.StatusNotFound, "Ошибка обновления пункта чек-листа: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, struct{}{})
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			WriteError(w, http.StatusBadRequest, "Не указан ID пункта чек-листа")
+			return
+		}
+		if err := checkSubtaskTask(id); err != nil {
+			WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err := db.DeleteSubtask(id); err != nil {
+			WriteError(w, http.StatusNotFound, "Ошибка удаления пункта чек-листа: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, struct{}{})
+	default:
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+	}
+}
+
+// ReorderSubtasksHandler обрабатывает HTTP запросы для изменения порядка пунктов чек-листа.
+// В теле запроса передаются ID задачи и ID всех её пунктов в новом порядке.
+func ReorderSubtasksHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+		return
+	}
+	var req ReorderReq
+	if err := readJSON(r, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := db.GetTask(req.TaskID); err != nil {
+		WriteError(w, http.StatusNotFound, "Задача не найдена")
+		return
+	}
+	if err := db.ReorderSubtasks(req.TaskID, req.IDs); err != nil {
+		WriteError(w, http.StatusBadRequest, "Ошибка изменения порядка пунктов чек-листа: "+err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, struct{}{})
+}
+
+// checkSubtaskTask проверяет, что пункт чек-листа с ID id существует и относится
+// к задаче, которая не находится в корзине.
+func checkSubtaskTask(id string) error {
+	subtask, err := db.GetSubtask(id)
+	if err != nil {
+		return fmt.Errorf("пункт чек-листа не найден: %w", err)
+	}
+	if _, err := db.GetTask(subtask.TaskID); err != nil {
+		return fmt.Errorf("задача не найдена")
+	}
+	return nil
+}
+
+// readSubtask читает пункт чек-листа из тела запроса и проверяет его название.
+func readSubtask(r *http.Request, subtask *db.Subtask) error {
+	if err := readJSON(r, subtask); err != nil {
+		return err
+	}
+	subtask.Title = strings.TrimSpace(subtask.Title)
+	if subtask.Title == "" {
+		return fmt.Errorf("не указано название пункта чек-листа")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"go1f/pkg/db"
+)
+
+// maxTagLength — наибольшая длина названия метки в символах.
+const maxTagLength = 64
+
+// TagsResp представляет собой структуру для ответа со списком меток в формате JSON.
+type TagsResp struct {
+	Tags []*db.Tag `json:"tags"`
+}
+
+// TagsHandler обрабатывает HTTP запросы для работы с метками задач.
+func TagsHandler(w http.ResponseWriter, r *http.Request) {
+	log.Printf("Получен запрос по пути: %s, метод: %s\n", r.URL.Path, r.Method)
+	switch r.Method {
+	case http.MethodGet:
+		list, err := db.Tags()
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка получения меток: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, TagsResp{Tags: list})
+	case http.MethodPost:
+		var tag db.Tag
+		if err := readTag(r, &tag); err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		id, err := db.AddTag(tag.Name)
+		if err != nil {
+			writeTagError(w, err)
+			return
+		}
+		log.Printf("Добавлена метка %q с ID %d\n", tag.Name, id)
+		WriteJSON(w, http.StatusOK, ResponseID{ID: fmt.Sprintf("%d", id)})
+	case http.MethodPut:
+		var tag db.Tag
+		if err := readTag(r, &tag); err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if tag.ID == "" {
+			WriteError(w, http.StatusBadRequest, "Не указан ID метки")
+			return
+		}
+		if err := db.UpdateTag(&tag); err != nil {
+			writeTagError(w, err)
+			return
+		}
+		WriteJSON(w, http.StatusOK, struct{}{})
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			WriteError(w, http.StatusBadRequest, "Не указан ID метки")
+			return
+		}
+		if err := db.DeleteTag(id); err != nil {
+			WriteError(w, http.StatusNotFound, "Ошибка удаления метки: "+err.Error())
+			return
+		}
+		WriteJSON(w, http.StatusOK, struct{}{})
+	default:
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+	}
+}
+
+// readTag читает метку из тела запроса и приводит её название к каноническому виду.
+func readTag(r *http.Request, tag *db.Tag) error {
+	if err := readJSON(r, tag); err != nil {
+		return err
+	}
+	name, err := normalizeTag(tag.Name)
+	if err != nil {
+		return err
+	}
+	tag.Name = name
+	return nil
+}
+
+// writeTagError записывает в ответ ошибку сохранения метки.
+func writeTagError(w http.ResponseWriter, err error) {
+	if errors.Is(err, db.ErrTagExists) {
+		WriteError(w, http.StatusConflict, "Ошибка сохранения метки: "+err.Error())
+		return
+	}
+	WriteError(w, http.StatusInternalServerError, "Ошибка сохранения метки: "+err.Error())
+}
+
+// normalizeTag приводит название метки к каноническому виду: без пробелов по краям
+// и без ведущего символа #, так что "#home" и "home" — одна и та же метка.
+func normalizeTag(name string) (string, error) {
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	if name == "" {
+		return "", errors.New("не указано название метки")
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return "", fmt.Errorf("название метки длиннее %d символов", maxTagLength)
+	}
+	if strings.Contains(name, ",") {
+		return "", fmt.Errorf("название метки %q не может содержать запятую", name)
+	}
+	return name, nil
+}
+
+// normalizeTags приводит названия меток к каноническому виду и убирает повторы без учёта регистра.
+// Для nil возвращается nil, чтобы отличать отсутствие меток в запросе от пустого списка.
+func normalizeTags(names []string) ([]string, error) {
+	if names == nil {
+		return nil, nil
+	}
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, name := range names {
+		tag, err := normalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		if key := strings.ToLower(tag); !seen[key] {
+			seen[key] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
@@ -1,9 +1,15 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"go1f/pkg/db"
+	"go1f/pkg/repeat"
 )
 
 // TasksResp представляет собой структуру для ответа с задачами в формате JSON.
@@ -13,12 +19,101 @@ type TasksResp struct {
 
 // GetTasksHandler обрабатывает HTTP запросы для получения списка задач.
 func GetTasksHandler(w http.ResponseWriter, r *http.Request) {
-	tasks, err := db.Tasks(w, 50) // в параметре максимальное количество записей
+	layout, err := outputLayout(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter, err := taskFilter(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tasks, err := db.Tasks(w, 50, filter) // в параметре максимальное количество записей
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, "Ошибка получения задач: "+err.Error())
 		return
 	}
+	lang := requestLang(r)
+	for _, task := range tasks {
+		fillRepeat(task, lang)
+		task.Date = formatDate(task.Date, layout)
+	}
 	WriteJSON(w, http.StatusAccepted, TasksResp{
 		Tasks: tasks,
 	})
 }
+
+// taskFilter разбирает параметры отбора списка задач:
+// sort — порядок (date или priority), tags — метки через запятую,
+// match — any (хотя бы одна из меток, по умолчанию) или all (все метки),
+// list_id — ID списка задач, 0 для «Входящих».
+func taskFilter(r *http.Request) (db.TaskFilter, error) {
+	var filter db.TaskFilter
+	query := r.URL.Query()
+
+	filter.Order = query.Get("sort")
+	if filter.Order != "" && filter.Order != db.OrderDate && filter.Order != db.OrderPriority {
+		return filter, errors.New("Параметр 'sort' должен быть date или priority")
+	}
+
+	if value := query.Get("tags"); value != "" {
+		tags, err := normalizeTags(strings.Split(value, ","))
+		if err != nil {
+			return filter, fmt.Errorf("Некорректный параметр 'tags': %w", err)
+		}
+		filter.Tags = tags
+	}
+	switch query.Get("match") {
+	case "", "any":
+	case "all":
+		filter.AllTags = true
+	default:
+		return filter, errors.New("Параметр 'match' должен быть any или all")
+	}
+
+	if value := query.Get("list_id"); value != "" {
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return filter, errors.New("Параметр 'list_id' должен быть ID списка задач")
+		}
+		filter.ListID = value
+	}
+	return filter, nil
+}
+
+// fillRepeat заполняет вычисляемые поля правила повторения задачи: описание на языке lang
+// и форму RRULE, если правило в ней выражается. Дата задачи должна быть ещё в формате 20060102.
+func fillRepeat(task *db.Task, lang string) {
+	if task.Repeat == "" {
+		return
+	}
+	rule, err := repeat.Parse(task.Repeat)
+	if err != nil {
+		return
+	}
+	task.RepeatText = repeat.Describe(rule, lang)
+	start, _ := time.Parse("20060102", task.Date)
+	if rrule, err := repeat.ToRRULE(rule, start); err == nil {
+		task.RRule = rrule
+	}
+}
+
+// requestLang возвращает язык ответа по заголовку Accept-Language: LangEN или LangRU.
+// Учитываются веса q; если ни один из поддерживаемых языков не указан, используется русский.
+func requestLang(r *http.Request) string {
+	lang, best := repeat.LangRU, -1.0
+	for _, item := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if (base == repeat.LangRU || base == repeat.LangEN) && q > best {
+			lang, best = base, q
+		}
+	}
+	return lang
+}
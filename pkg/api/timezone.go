@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+	_ "time/tzdata" // База часовых поясов встраивается в бинарник, чтобы не зависеть от системы.
+)
+
+// defaultLocation — часовой пояс сервера по умолчанию. Задаётся переменной окружения TODO_TZ,
+// например TODO_TZ=Europe/Moscow; если она не задана, используется локальный часовой пояс.
+var defaultLocation = time.Local
+
+// initTimezone настраивает часовой пояс сервера по умолчанию.
+func initTimezone() {
+	name := os.Getenv("TODO_TZ")
+	if name == "" {
+		return
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("Некорректный часовой пояс в TODO_TZ %q, используется локальный: %v\n", name, err)
+		return
+	}
+	defaultLocation = loc
+	log.Printf("Часовой пояс по умолчанию: %s\n", loc)
+}
+
+// requestLocation возвращает часовой пояс запроса. Он берётся из заголовка X-Timezone
+// или параметра tz, иначе используется часовой пояс сервера по умолчанию.
+func requestLocation(r *http.Request) (*time.Location, error) {
+	name := r.Header.Get("X-Timezone")
+	if name == "" {
+		name = r.URL.Query().Get("tz")
+	}
+	if name == "" {
+		return defaultLocation, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный часовой пояс %q", name)
+	}
+	return loc, nil
+}
+
+// requestNow возвращает текущее время в часовом поясе запроса.
+// Все проверки «дата в прошлом» выполняются относительно этого времени.
+func requestNow(r *http.Request) (time.Time, error) {
+	loc, err := requestLocation(r)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().In(loc), nil
+}
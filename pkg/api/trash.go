@@ -0,0 +1,122 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go1f/pkg/db"
+)
+
+// trashRetention — сколько хранятся задачи в корзине перед окончательным удалением.
+// Задаётся в днях переменной окружения TODO_TRASH_DAYS; 0 отключает автоматическую очистку.
+var trashRetention = 30 * 24 * time.Hour
+
+// trashPurgeInterval — как часто проверяется, не пора ли окончательно удалить задачи из корзины.
+const trashPurgeInterval = time.Hour
+
+// initTrash настраивает срок хранения задач в корзине и запускает её периодическую очистку.
+func initTrash() {
+	if value := os.Getenv("TODO_TRASH_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			log.Printf("Некорректный срок хранения корзины в TODO_TRASH_DAYS %q, используется %v\n", value, trashRetention)
+		} else {
+			trashRetention = time.Duration(days) * 24 * time.Hour
+		}
+	}
+	if trashRetention == 0 {
+		log.Println("Автоматическая очистка корзины отключена")
+		return
+	}
+	purgeExpiredTrash()
+	go func() {
+		for range time.Tick(trashPurgeInterval) {
+			purgeExpiredTrash()
+		}
+	}()
+}
+
+// purgeExpiredTrash окончательно удаляет задачи, пролежавшие в корзине дольше trashRetention.
+func purgeExpiredTrash() {
+	before := time.Now().Add(-trashRetention).UTC().Format(time.RFC3339)
+	count, err := db.PurgeTrash(before)
+	if err != nil {
+		log.Printf("Ошибка очистки корзины: %v\n", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Из корзины окончательно удалено задач: %d\n", count)
+	}
+}
+
+// TrashHandler обрабатывает HTTP запросы для получения списка задач в корзине.
+func TrashHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+		return
+	}
+	layout, err := outputLayout(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tasks, err := db.TrashedTasks(50)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, "Ошибка получения задач из корзины: "+err.Error())
+		return
+	}
+	lang := requestLang(r)
+	for _, task := range tasks {
+		fillRepeat(task, lang)
+		task.Date = formatDate(task.Date, layout)
+	}
+	WriteJSON(w, http.StatusOK, TasksResp{Tasks: tasks})
+}
+
+// RestoreHandler обрабатывает HTTP запросы для восстановления задачи из корзины по ID.
+func RestoreHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+		return
+	}
+	taskID := r.URL.Query().Get("id")
+	if taskID == "" {
+		WriteError(w, http.StatusBadRequest, "Не указан ID задачи")
+		return
+	}
+	if err := db.RestoreTask(taskID); err != nil {
+		WriteError(w, http.StatusNotFound, "Ошибка восстановления задачи: "+err.Error())
+		return
+	}
+	log.Printf("Задача с ID %s восстановлена из корзины\n", taskID)
+	WriteJSON(w, http.StatusOK, struct{}{})
+}
+
+// PurgeHandler обрабатывает HTTP запросы для окончательного удаления задач из корзины.
+// С параметром id удаляется одна задача, без него корзина очищается целиком.
+func PurgeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+		return
+	}
+	taskID := r.URL.Query().Get("id")
+	if taskID == "" {
+		count, err := db.PurgeTrash("")
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, "Ошибка очистки корзины: "+err.Error())
+			return
+		}
+		log.Printf("Корзина очищена, удалено задач: %d\n", count)
+		WriteJSON(w, http.StatusOK, struct{}{})
+		return
+	}
+	if err := db.PurgeTask(taskID); err != nil {
+		WriteError(w, http.StatusNotFound, "Ошибка удаления задачи из корзины: "+err.Error())
+		return
+	}
+	log.Printf("Задача с ID %s окончательно удалена из корзины\n", taskID)
+	WriteJSON(w, http.StatusOK, struct{}{})
+}
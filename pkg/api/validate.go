@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"go1f/pkg/repeat"
+)
+
+// ValidateResp представляет собой результат проверки правила повторения.
+type ValidateResp struct {
+	Valid bool `json:"valid"`
+	// Repeat, RepeatText и RRule заполняются для корректного правила: каноническая запись,
+	// описание на языке запроса и форма RRULE, если правило в ней выражается.
+	Repeat     string `json:"repeat,omitempty"`
+	RepeatText string `json:"repeat_text,omitempty"`
+	RRule      string `json:"rrule,omitempty"`
+	// Error и Details заполняются для некорректного правила.
+	Error   string             `json:"error,omitempty"`
+	Details *repeat.ParseError `json:"details,omitempty"`
+}
+
+// ValidateRepeatHandler обрабатывает HTTP запросы для проверки правила повторения.
+// Правило передаётся параметром repeat (GET) или в теле запроса {"repeat": "..."} (POST).
+// Необязательная дата задачи date нужна для точной проверки и формы RRULE: например, ежегодное
+// правило для задачи на 29 февраля в RRULE не выражается.
+// Некорректное правило — не ошибка запроса, поэтому ответ всегда имеет статус 200.
+func ValidateRepeatHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Repeat string `json:"repeat"`
+		Date   string `json:"date"`
+	}
+	switch r.Method {
+	case http.MethodGet:
+		req.Repeat = r.URL.Query().Get("repeat")
+		req.Date = r.URL.Query().Get("date")
+	case http.MethodPost:
+		if err := readJSON(r, &req); err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		WriteError(w, http.StatusMethodNotAllowed, "Метод не поддерживается")
+		return
+	}
+
+	var start time.Time
+	if req.Date != "" {
+		date, err := parseDate(req.Date)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		start = date
+	}
+
+	rule, err := repeat.Parse(req.Repeat)
+	if err == nil {
+		err = repeat.CheckRRULEStart(req.Repeat, rule, start)
+	}
+	if err != nil {
+		resp := ValidateResp{Error: err.Error()}
+		errors.As(err, &resp.Details)
+		WriteJSON(w, http.StatusOK, resp)
+		return
+	}
+	resp := ValidateResp{
+		Valid:      true,
+		Repeat:     rule.String(),
+		RepeatText: repeat.Describe(rule, requestLang(r)),
+	}
+	if rrule, err := repeat.ToRRULE(rule, start); err == nil {
+		resp.RRule = rrule
+	}
+	WriteJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Completion представляет собой запись истории о выполнении задачи.
+// Название сохраняется на момент выполнения, поэтому запись не зависит от дальнейших
+// изменений задачи и остаётся в истории после её окончательного удаления.
+type Completion struct {
+	ID     string `json:"id"`
+	TaskID string `json:"task_id"`
+	// Date — дата, на которую было запланировано выполненное повторение задачи.
+	Date  string `json:"date"`
+	Title string `json:"title"`
+	// DoneAt — время выполнения в формате RFC 3339.
+	DoneAt string `json:"done_at"`
+}
+
+// HistoryFilter задаёт отбор записей истории выполнения.
+// From и To — границы времени выполнения: From включается, To не включается.
+// Нулевые границы и пустой TaskID не ограничивают выборку.
+type HistoryFilter struct {
+	From   time.Time
+	To     time.Time
+	TaskID string
+}
+
+// CompleteTask отмечает выполнение задачи в одной транзакции. Задача done в том виде,
+// в котором она была выполнена, записывается в историю в момент doneAt. Если next не nil,
+// задача сохраняется с новой датой из next, её чек-лист сбрасывается, а зависящие от неё задачи
+// разблокируются; иначе повторений больше нет и задача перемещается в корзину.
+func CompleteTask(done *Task, next *Task, doneAt time.Time) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := addCompletion(tx, done, doneAt); err != nil {
+		return err
+	}
+	if next == nil {
+		if err := trashTask(tx, done.ID); err != nil {
+			return err
+		}
+	} else {
+		if err := updateTask(tx, next); err != nil {
+			return err
+		}
+		// Чек-лист относится к одному повторению, поэтому на новую дату он переходит невыполненным.
+		if err := resetSubtasks(tx, next.ID); err != nil {
+			return err
+		}
+		// Выполненное повторение снимает блокировку с зависящих от задачи задач.
+		if err := unblockDependents(tx, next.ID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// addCompletion записывает в историю выполнение задачи task в момент doneAt.
+func addCompletion(tx *sql.Tx, task *Task, doneAt time.Time) error {
+	idInt, err := strconv.ParseInt(task.ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	query := `INSERT INTO completions (task_id, date, title, done_at) VALUES (?, ?, ?, ?)`
+	_, err = tx.Exec(query, idInt, task.Date, task.Title, doneAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("ошибка записи выполнения задачи в историю: %w", err)
+	}
+	return nil
+}
+
+// History возвращает не более limit записей истории выполнения, начиная с последних.
+func History(limit int, filter HistoryFilter) ([]*Completion, error) {
+	query := `SELECT id, task_id, date, title, done_at FROM completions WHERE 1 = 1`
+	args := []any{}
+	if !filter.From.IsZero() {
+		query += ` AND done_at >= ?`
+		args = append(args, filter.From.UTC().Format(time.RFC3339))
+	}
+	if !filter.To.IsZero() {
+		query += ` AND done_at < ?`
+		args = append(args, filter.To.UTC().Format(time.RFC3339))
+	}
+	if filter.TaskID != "" {
+		idInt, err := strconv.ParseInt(filter.TaskID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный формат ID: %w", err)
+		}
+		query += ` AND task_id = ?`
+		args = append(args, idInt)
+	}
+	query += ` ORDER BY done_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса к БД: %w", err)
+	}
+	defer rows.Close()
+
+	completions := []*Completion{}
+	for rows.Next() {
+		var c Completion
+		if err := rows.Scan(&c.ID, &c.TaskID, &c.Date, &c.Title, &c.DoneAt); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		completions = append(completions, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return completions, nil
+}
@@ -3,7 +3,6 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"os"
 
 	_ "modernc.org/sqlite"
 )
@@ -18,19 +17,90 @@ CREATE TABLE IF NOT EXISTS scheduler (
     title VARCHAR(255) NOT NULL,
     comment TEXT,
     date TEXT NOT NULL,
-    repeat VARCHAR(100)
+    repeat VARCHAR(100),
+    time CHAR(5) NOT NULL DEFAULT '',
+    priority INTEGER NOT NULL DEFAULT 0,
+    list_id INTEGER NOT NULL DEFAULT 0,
+    deleted_at TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX IF NOT EXISTS idx_scheduler_date ON scheduler(date);
+
+CREATE TABLE IF NOT EXISTS exceptions (
+    task_id INTEGER NOT NULL,
+    date CHAR(8) NOT NULL,
+    PRIMARY KEY (task_id, date)
+);
+
+CREATE TABLE IF NOT EXISTS holidays (
+    date CHAR(8) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(64) NOT NULL UNIQUE COLLATE NOCASE
+);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags(tag_id);
+
+CREATE TABLE IF NOT EXISTS lists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS subtasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    done INTEGER NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_subtasks_task ON subtasks(task_id);
+
+CREATE TABLE IF NOT EXISTS dependencies (
+    task_id INTEGER NOT NULL,
+    blocker_id INTEGER NOT NULL,
+    PRIMARY KEY (task_id, blocker_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_dependencies_blocker ON dependencies(blocker_id);
+
+CREATE TABLE IF NOT EXISTS completions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id INTEGER NOT NULL,
+    date CHAR(8) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    done_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_completions_done_at ON completions(done_at);
 `
 
+// columns перечисляет столбцы, добавленные в таблицы после первой версии схемы.
+// В существующие БД они добавляются при запуске через ALTER TABLE.
+var columns = []struct {
+	table      string
+	name       string
+	definition string
+}{
+	{"scheduler", "time", "CHAR(5) NOT NULL DEFAULT ''"},
+	{"scheduler", "priority", "INTEGER NOT NULL DEFAULT 0"},
+	{"scheduler", "list_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"scheduler", "deleted_at", "TEXT NOT NULL DEFAULT ''"},
+}
+
 // Init инициализирует базу данных, создавая таблицы, если они не существуют.
+// Схема применяется при каждом запуске, чтобы таблицы, добавленные в новых версиях,
+// появлялись и в уже существующих БД.
 func Init(dbFile string) error {
-	install := false
-	if _, err := os.Stat(dbFile); os.IsNotExist(err) {
-		install = true
-	}
-
 	database, err := sql.Open("sqlite", dbFile)
 	if err != nil {
 		return fmt.Errorf("ошибка при открытии БД: %w", err)
@@ -40,13 +110,33 @@ func Init(dbFile string) error {
 		return fmt.Errorf("ошибка при пинге БД: %w", err)
 	}
 
-	if install {
-		_, err := database.Exec(schema)
-		if err != nil {
-			return fmt.Errorf("ошибка при инициализации схемы: %w", err)
-		}
+	if _, err := database.Exec(schema); err != nil {
+		return fmt.Errorf("ошибка при инициализации схемы: %w", err)
+	}
+	if err := migrate(database); err != nil {
+		return fmt.Errorf("ошибка при обновлении схемы: %w", err)
 	}
 
 	DB = database
 	return nil
 }
+
+// migrate добавляет в таблицы недостающие столбцы из columns.
+func migrate(database *sql.DB) error {
+	for _, column := range columns {
+		var count int
+		err := database.QueryRow(`SELECT count(*) FROM pragma_table_info(?) WHERE name = ?`,
+			column.table, column.name).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения структуры таблицы %s: %w", column.table, err)
+		}
+		if count > 0 {
+			continue
+		}
+		query := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, column.table, column.name, column.definition)
+		if _, err := database.Exec(query); err != nil {
+			return fmt.Errorf("ошибка добавления столбца %s.%s: %w", column.table, column.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrDependencyCycle возвращается, если новая зависимость замкнула бы цикл между задачами.
+var ErrDependencyCycle = errors.New("зависимость образует цикл между задачами")
+
+// Dependencies возвращает ID задач, которые блокируют задачу taskID, и ID задач,
+// которые блокирует она сама. Задачи из корзины не учитываются.
+func Dependencies(taskID string) (blockedBy []string, blocks []string, err error) {
+	idInt, err := strconv.ParseInt(taskID, 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	blockedBy, err = queryIDs(`SELECT d.blocker_id FROM dependencies d
+	JOIN scheduler s ON s.id = d.blocker_id AND s.deleted_at = ''
+	WHERE d.task_id = ? ORDER BY d.blocker_id`, idInt)
+	if err != nil {
+		return nil, nil, err
+	}
+	blocks, err = queryIDs(`SELECT d.task_id FROM dependencies d
+	JOIN scheduler s ON s.id = d.task_id AND s.deleted_at = ''
+	WHERE d.blocker_id = ? ORDER BY d.task_id`, idInt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return blockedBy, blocks, nil
+}
+
+// queryIDs выполняет запрос, возвращающий один столбец с ID, и собирает их в список.
+func queryIDs(query string, args ...any) ([]string, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса к БД: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return ids, nil
+}
+
+// AddDependency отмечает, что задача taskID заблокирована задачей blockerID.
+// Если blockerID уже прямо или косвенно заблокирована задачей taskID, возвращается ErrDependencyCycle.
+// Повторное добавление той же зависимости не является ошибкой.
+func AddDependency(taskID, blockerID string) error {
+	idInt, err := strconv.ParseInt(taskID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	blockerInt, err := strconv.ParseInt(blockerID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID блокирующей задачи: %w", err)
+	}
+	if idInt == blockerInt {
+		return ErrDependencyCycle
+	}
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Обходим цепочку задач, блокирующих blockerID; если в ней есть taskID, зависимость замкнёт цикл.
+	query := `WITH RECURSIVE chain(id) AS (
+		SELECT blocker_id FROM dependencies WHERE task_id = ?
+		UNION
+		SELECT d.blocker_id FROM dependencies d JOIN chain c ON d.task_id = c.id
+	)
+	SELECT count(*) FROM chain WHERE id = ?`
+	var count int
+	if err := tx.QueryRow(query, blockerInt, idInt).Scan(&count); err != nil {
+		return fmt.Errorf("ошибка проверки зависимостей задачи: %w", err)
+	}
+	if count > 0 {
+		return ErrDependencyCycle
+	}
+	_, err = tx.Exec(`INSERT OR IGNORE INTO dependencies (task_id, blocker_id) VALUES (?, ?)`, idInt, blockerInt)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления зависимости в БД: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// DeleteDependency удаляет зависимость задачи taskID от задачи blockerID.
+func DeleteDependency(taskID, blockerID string) error {
+	res, err := DB.Exec(`DELETE FROM dependencies WHERE task_id = ? AND blocker_id = ?`, taskID, blockerID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления зависимости из БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("задача с ID %s не зависит от задачи с ID %s", taskID, blockerID)
+	}
+	return nil
+}
+
+// unblockDependents снимает блокировку задачей blockerID со всех зависящих от неё задач.
+// Вызывается при выполнении задачи.
+func unblockDependents(tx *sql.Tx, blockerID string) error {
+	if _, err := tx.Exec(`DELETE FROM dependencies WHERE blocker_id = ?`, blockerID); err != nil {
+		return fmt.Errorf("ошибка снятия блокировки с зависимых задач: %w", err)
+	}
+	return nil
+}
+
+// fillBlocked отмечает задачи из tasks, у которых есть незавершённые блокирующие задачи.
+// Задачи в корзине других задач не блокируют.
+func fillBlocked(tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	byID := make(map[string]*Task, len(tasks))
+	args := make([]any, len(tasks))
+	for i, task := range tasks {
+		byID[task.ID] = task
+		args[i] = task.ID
+	}
+	ids, err := queryIDs(`SELECT DISTINCT d.task_id FROM dependencies d
+	JOIN scheduler s ON s.id = d.blocker_id AND s.deleted_at = ''
+	WHERE d.task_id IN (`+placeholders(len(tasks))+`)`, args...)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if task, ok := byID[id]; ok {
+			task.Blocked = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Exceptions возвращает даты-исключения задачи — даты, в которые повторение пропускается.
+func Exceptions(taskID string) ([]string, error) {
+	idInt, err := strconv.ParseInt(taskID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	rows, err := DB.Query(`SELECT date FROM exceptions WHERE task_id = ? ORDER BY date`, idInt)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса к БД: %w", err)
+	}
+	defer rows.Close()
+
+	dates := []string{}
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		dates = append(dates, date)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return dates, nil
+}
+
+// AddException добавляет дату-исключение для задачи. Повторное добавление той же даты не является ошибкой.
+func AddException(taskID string, date string) error {
+	idInt, err := strconv.ParseInt(taskID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	_, err = DB.Exec(`INSERT OR IGNORE INTO exceptions (task_id, date) VALUES (?, ?)`, idInt, date)
+	if err != nil {
+		return fmt.Errorf("ошибка добавления даты-исключения в БД: %w", err)
+	}
+	return nil
+}
+
+// SkipTask пропускает повторение задачи taskID в одной транзакции. Непустая дата date
+// добавляется в даты-исключения задачи. Если next не nil, задача сохраняется с новой датой
+// из next; иначе пропущенное повторение было последним и задача перемещается в корзину.
+func SkipTask(taskID string, date string, next *Task) error {
+	idInt, err := strconv.ParseInt(taskID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if date != "" {
+		_, err = tx.Exec(`INSERT OR IGNORE INTO exceptions (task_id, date) VALUES (?, ?)`, idInt, date)
+		if err != nil {
+			return fmt.Errorf("ошибка добавления даты-исключения в БД: %w", err)
+		}
+	}
+	if next == nil {
+		err = trashTask(tx, taskID)
+	} else {
+		err = updateTask(tx, next)
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// DeleteException удаляет дату-исключение задачи.
+func DeleteException(taskID string, date string) error {
+	idInt, err := strconv.ParseInt(taskID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	res, err := DB.Exec(`DELETE FROM exceptions WHERE task_id = ? AND date = ?`, idInt, date)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления даты-исключения из БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("дата-исключение %s для задачи с ID %s не найдена", date, taskID)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+)
+
+// Holiday представляет собой нерабочий праздничный день.
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// Holidays возвращает все праздничные дни, упорядоченные по дате.
+func Holidays() ([]*Holiday, error) {
+	rows, err := DB.Query(`SELECT date, name FROM holidays ORDER BY date`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса к БД: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []*Holiday{}
+	for rows.Next() {
+		var holiday Holiday
+		if err := rows.Scan(&holiday.Date, &holiday.Name); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		holidays = append(holidays, &holiday)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return holidays, nil
+}
+
+// AddHolidays добавляет праздничные дни в одной транзакции.
+// Если день уже есть в календаре, его название обновляется.
+func AddHolidays(holidays []*Holiday) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO holidays (date, name) VALUES (?, ?)
+	ON CONFLICT(date) DO UPDATE SET name = excluded.name`
+	for _, holiday := range holidays {
+		if _, err := tx.Exec(query, holiday.Date, holiday.Name); err != nil {
+			return fmt.Errorf("ошибка добавления праздничного дня %s в БД: %w", holiday.Date, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// DeleteHoliday удаляет праздничный день по дате.
+func DeleteHoliday(date string) error {
+	res, err := DB.Exec(`DELETE FROM holidays WHERE date = ?`, date)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления праздничного дня из БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("праздничный день %s не найден для удаления", date)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// InboxID — ID списка «Входящие», в котором находятся задачи, не отнесённые ни к одному списку.
+// Этот список не хранится в таблице lists и не может быть удалён.
+const InboxID = "0"
+
+// List представляет собой список задач, например проект или область жизни.
+type List struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Lists возвращает все списки задач, упорядоченные по названию.
+func Lists() ([]*List, error) {
+	rows, err := DB.Query(`SELECT id, name FROM lists ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса к БД: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []*List{}
+	for rows.Next() {
+		var list List
+		if err := rows.Scan(&list.ID, &list.Name); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		lists = append(lists, &list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return lists, nil
+}
+
+// GetList возвращает список задач по ID.
+func GetList(id string) (*List, error) {
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	var list List
+	err = DB.QueryRow(`SELECT id, name FROM lists WHERE id = ?`, idInt).Scan(&list.ID, &list.Name)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("список с ID %s не найден", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при сканировании строки списка: %w", err)
+	}
+	return &list, nil
+}
+
+// AddList добавляет список задач и возвращает его ID.
+func AddList(name string) (int64, error) {
+	res, err := DB.Exec(`INSERT INTO lists (name) VALUES (?)`, name)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка добавления списка в БД: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения ID последней вставленной записи: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateList переименовывает список задач с ID list.ID.
+func UpdateList(list *List) error {
+	res, err := DB.Exec(`UPDATE lists SET name = ? WHERE id = ?`, list.Name, list.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления списка в БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("список с ID %s не найден", list.ID)
+	}
+	return nil
+}
+
+// DeleteList удаляет список задач по ID. Если cascade равно true, задачи списка
+// перемещаются в корзину, иначе они переносятся во «Входящие». Задачи, восстановленные
+// из корзины после удаления списка, попадают во «Входящие».
+func DeleteList(id string, cascade bool) error {
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM lists WHERE id = ?`, idInt)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления списка из БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("список с ID %s не найден для удаления", id)
+	}
+	if cascade {
+		deletedAt := time.Now().UTC().Format(time.RFC3339)
+		query := `UPDATE scheduler SET deleted_at = ? WHERE list_id = ? AND deleted_at = ''`
+		if _, err := tx.Exec(query, deletedAt, idInt); err != nil {
+			return fmt.Errorf("ошибка перемещения задач списка в корзину: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`UPDATE scheduler SET list_id = 0 WHERE list_id = ?`, idInt); err != nil {
+		return fmt.Errorf("ошибка переноса задач во «Входящие»: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// Subtask представляет собой пункт чек-листа задачи.
+type Subtask struct {
+	ID     string `json:"id"`
+	TaskID string `json:"task_id"`
+	Title  string `json:"title"`
+	Done   bool   `json:"done"`
+	// Position — порядковый номер пункта в чек-листе, начиная с 1.
+	Position int `json:"position"`
+}
+
+// Progress — сколько пунктов чек-листа задачи выполнено из общего числа.
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// Subtasks возвращает пункты чек-листа задачи taskID в порядке их расположения.
+func Subtasks(taskID string) ([]*Subtask, error) {
+	rows, err := DB.Query(`SELECT id, task_id, title, done, position FROM subtasks
+	WHERE task_id = ? ORDER BY position, id`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса к БД: %w", err)
+	}
+	defer rows.Close()
+
+	subtasks := []*Subtask{}
+	for rows.Next() {
+		var subtask Subtask
+		if err := rows.Scan(&subtask.ID, &subtask.TaskID, &subtask.Title, &subtask.Done, &subtask.Position); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		subtasks = append(subtasks, &subtask)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return subtasks, nil
+}
+
+// GetSubtask возвращает пункт чек-листа по ID.
+func GetSubtask(id string) (*Subtask, error) {
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	var subtask Subtask
+	err = DB.QueryRow(`SELECT id, task_id, title, done, position FROM subtasks WHERE id = ?`, idInt).
+		Scan(&subtask.ID, &subtask.TaskID, &subtask.Title, &subtask.Done, &subtask.Position)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("пункт чек-листа с ID %s не найден", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при сканировании строки пункта чек-листа: %w", err)
+	}
+	return &subtask, nil
+}
+
+// AddSubtask добавляет пункт в конец чек-листа задачи и возвращает его ID.
+func AddSubtask(subtask *Subtask) (int64, error) {
+	query := `INSERT INTO subtasks (task_id, title, done, position)
+	SELECT ?, ?, ?, COALESCE(MAX(position), 0) + 1 FROM subtasks WHERE task_id = ?`
+	res, err := DB.Exec(query, subtask.TaskID, subtask.Title, subtask.Done, subtask.TaskID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка добавления пункта чек-листа в БД: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения ID последней вставленной записи: %w", err)
+	}
+	subtask.ID = strconv.FormatInt(id, 10)
+	return id, nil
+}
+
+// UpdateSubtask обновляет название и отметку о выполнении пункта чек-листа.
+func UpdateSubtask(subtask *Subtask) error {
+	res, err := DB.Exec(`UPDATE subtasks SET title = ?, done = ? WHERE id = ?`, subtask.Title, subtask.Done, subtask.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления пункта чек-листа в БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("пункт чек-листа с ID %s не найден", subtask.ID)
+	}
+	return nil
+}
+
+// DeleteSubtask удаляет пункт чек-листа по ID.
+func DeleteSubtask(id string) error {
+	res, err := DB.Exec(`DELETE FROM subtasks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления пункта чек-листа из БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("пункт чек-листа с ID %s не найден для удаления", id)
+	}
+	return nil
+}
+
+// ReorderSubtasks располагает пункты чек-листа задачи taskID в порядке ids.
+// ids должен содержать все пункты чек-листа задачи ровно по одному разу.
+func ReorderSubtasks(taskID string, ids []string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	var total int
+	if err := tx.QueryRow(`SELECT count(*) FROM subtasks WHERE task_id = ?`, taskID).Scan(&total); err != nil {
+		return fmt.Errorf("ошибка подсчёта пунктов чек-листа: %w", err)
+	}
+	if total != len(ids) {
+		return fmt.Errorf("указано %d пунктов чек-листа, а у задачи их %d", len(ids), total)
+	}
+	seen := map[string]bool{}
+	for i, id := range ids {
+		if seen[id] {
+			return fmt.Errorf("пункт чек-листа с ID %s указан несколько раз", id)
+		}
+		seen[id] = true
+		res, err := tx.Exec(`UPDATE subtasks SET position = ? WHERE id = ? AND task_id = ?`, i+1, id, taskID)
+		if err != nil {
+			return fmt.Errorf("ошибка изменения порядка пунктов чек-листа: %w", err)
+		}
+		count, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("пункт чек-листа с ID %s не относится к задаче %s", id, taskID)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// resetSubtasks снимает отметки о выполнении со всех пунктов чек-листа задачи taskID.
+// Вызывается, когда повторяющаяся задача переносится на следующую дату.
+func resetSubtasks(tx *sql.Tx, taskID string) error {
+	if _, err := tx.Exec(`UPDATE subtasks SET done = 0 WHERE task_id = ?`, taskID); err != nil {
+		return fmt.Errorf("ошибка сброса чек-листа задачи: %w", err)
+	}
+	return nil
+}
+
+// fillProgress заполняет прогресс чек-листа у задач tasks одним запросом к БД.
+// У задач без чек-листа прогресс остаётся пустым.
+func fillProgress(tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	byID := make(map[string]*Task, len(tasks))
+	args := make([]any, len(tasks))
+	for i, task := range tasks {
+		byID[task.ID] = task
+		args[i] = task.ID
+	}
+	query := `SELECT task_id, SUM(done), count(*) FROM subtasks
+	WHERE task_id IN (` + placeholders(len(tasks)) + `) GROUP BY task_id`
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("ошибка получения прогресса чек-листов: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		var progress Progress
+		if err := rows.Scan(&taskID, &progress.Done, &progress.Total); err != nil {
+			return fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		if task, ok := byID[taskID]; ok {
+			task.Progress = &progress
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return nil
+}
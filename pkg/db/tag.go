@@ -0,0 +1,177 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tag представляет собой метку, которой можно отметить задачи, например home или work.
+type Tag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ErrTagExists возвращается, если метка с таким названием уже существует.
+var ErrTagExists = errors.New("метка с таким названием уже существует")
+
+// Tags возвращает все метки, упорядоченные по названию.
+func Tags() ([]*Tag, error) {
+	rows, err := DB.Query(`SELECT id, name FROM tags ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса к БД: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*Tag{}
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return tags, nil
+}
+
+// AddTag добавляет метку и возвращает её ID. Названия меток не зависят от регистра
+// и не могут повторяться.
+func AddTag(name string) (int64, error) {
+	if err := checkTagName(name, ""); err != nil {
+		return 0, err
+	}
+	res, err := DB.Exec(`INSERT INTO tags (name) VALUES (?)`, name)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка добавления метки %q в БД: %w", name, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения ID последней вставленной записи: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateTag переименовывает метку с ID tag.ID.
+func UpdateTag(tag *Tag) error {
+	if err := checkTagName(tag.Name, tag.ID); err != nil {
+		return err
+	}
+	res, err := DB.Exec(`UPDATE tags SET name = ? WHERE id = ?`, tag.Name, tag.ID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления метки в БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("метка с ID %s не найдена", tag.ID)
+	}
+	return nil
+}
+
+// checkTagName проверяет, что название name не занято другой меткой, кроме метки с ID exceptID.
+func checkTagName(name string, exceptID string) error {
+	var count int
+	err := DB.QueryRow(`SELECT count(*) FROM tags WHERE name = ? AND id != ?`, name, exceptID).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки названия метки: %w", err)
+	}
+	if count > 0 {
+		return ErrTagExists
+	}
+	return nil
+}
+
+// DeleteTag удаляет метку по ID и снимает её со всех задач.
+func DeleteTag(id string) error {
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM tags WHERE id = ?`, idInt)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления метки из БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("метка с ID %s не найдена для удаления", id)
+	}
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE tag_id = ?`, idInt); err != nil {
+		return fmt.Errorf("ошибка удаления связей метки с задачами: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// setTaskTags заменяет метки задачи taskID на names. Метки, которых ещё нет, создаются.
+func setTaskTags(tx *sql.Tx, taskID int64, names []string) error {
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, taskID); err != nil {
+		return fmt.Errorf("ошибка удаления меток задачи: %w", err)
+	}
+	for _, name := range names {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO tags (name) VALUES (?)`, name); err != nil {
+			return fmt.Errorf("ошибка добавления метки %q в БД: %w", name, err)
+		}
+		query := `INSERT OR IGNORE INTO task_tags (task_id, tag_id) SELECT ?, id FROM tags WHERE name = ?`
+		if _, err := tx.Exec(query, taskID, name); err != nil {
+			return fmt.Errorf("ошибка добавления метки %q задаче: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// fillTags заполняет метки у задач tasks одним запросом к БД.
+func fillTags(tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	byID := make(map[string]*Task, len(tasks))
+	args := make([]any, len(tasks))
+	for i, task := range tasks {
+		byID[task.ID] = task
+		args[i] = task.ID
+	}
+	query := `SELECT tt.task_id, t.name FROM task_tags tt JOIN tags t ON t.id = tt.tag_id
+	WHERE tt.task_id IN (` + placeholders(len(tasks)) + `) ORDER BY t.name`
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("ошибка получения меток задач: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, name string
+		if err := rows.Scan(&taskID, &name); err != nil {
+			return fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		if task, ok := byID[taskID]; ok {
+			task.Tags = append(task.Tags, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка при чтении строк: %w", err)
+	}
+	return nil
+}
+
+// placeholders возвращает n параметров запроса через запятую: "?, ?, ?".
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
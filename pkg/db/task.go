@@ -2,18 +2,108 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// MaxPriority — наибольший допустимый приоритет задачи.
+const MaxPriority = 3
+
+// Priority — приоритет задачи. Как и ID задачи, в JSON он выдаётся строкой,
+// а принимается и строкой, и числом.
+type Priority int
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.Itoa(int(p)))
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var n int
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		var err error
+		if n, err = strconv.Atoi(s); err != nil {
+			return fmt.Errorf("приоритет задачи должен быть числом: %q", s)
+		}
+	} else if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*p = Priority(n)
+	return nil
+}
+
+// Порядки сортировки списка задач.
+const (
+	// OrderDate — по дате, а в пределах дня по убыванию приоритета.
+	OrderDate = "date"
+	// OrderPriority — по убыванию приоритета, а при равном приоритете по дате.
+	OrderPriority = "priority"
+)
+
+// orders сопоставляет порядок сортировки с выражением ORDER BY.
+// Пустой порядок — исходная сортировка по дате и времени.
+var orders = map[string]string{
+	"":            "date, time",
+	OrderDate:     "date, priority DESC, time",
+	OrderPriority: "priority DESC, date, time",
+	orderDeleted:  "deleted_at DESC, id DESC",
+}
+
+// orderDeleted — порядок задач в корзине: сначала удалённые последними.
+const orderDeleted = "deleted"
+
+// TaskFilter задаёт отбор и порядок задач для Tasks.
+type TaskFilter struct {
+	// Order — порядок сортировки: OrderDate, OrderPriority или пустая строка
+	// для сортировки по дате и времени.
+	Order string
+	// Tags — названия меток; пустой список означает задачи с любыми метками.
+	Tags []string
+	// AllTags означает, что задача должна иметь все метки из Tags, а не хотя бы одну.
+	AllTags bool
+	// ListID — ID списка задач, InboxID для «Входящих»; пустая строка означает задачи из всех списков.
+	ListID string
+	// Trashed означает, что отбираются задачи из корзины, а не обычные задачи.
+	Trashed bool
+}
+
 type Task struct {
 	ID      string `json:"id"`
 	Date    string `json:"date"`
 	Title   string `json:"title"`
 	Comment string `json:"comment"`
 	Repeat  string `json:"repeat"`
+	// Time — необязательное время задачи в формате 15:04. Пустая строка означает задачу на весь день.
+	Time string `json:"time"`
+	// Priority — приоритет задачи от 0 (обычная задача) до MaxPriority (самая важная).
+	Priority Priority `json:"priority"`
+	// Tags — названия меток задачи. При обновлении задачи отсутствие поля оставляет метки
+	// без изменений, а пустой список снимает все метки.
+	Tags []string `json:"tags,omitempty"`
+	// ListID — ID списка, к которому относится задача; пустая строка при выдаче означает «Входящие».
+	// При добавлении задачи пустая строка помещает её во «Входящие», а при обновлении
+	// оставляет список без изменений; для переноса во «Входящие» указывается InboxID.
+	ListID string `json:"list_id,omitempty"`
+	// Progress — прогресс чек-листа задачи. Вычисляется при выдаче задачи
+	// и отсутствует, если у задачи нет пунктов чек-листа.
+	Progress *Progress `json:"progress,omitempty"`
+	// Blocked — задача заблокирована другими, ещё не выполненными задачами. Вычисляется при выдаче задачи.
+	Blocked bool `json:"blocked,omitempty"`
+	// DeletedAt — время перемещения задачи в корзину в формате RFC 3339; пустая строка
+	// означает, что задача не удалена.
+	DeletedAt string `json:"deleted_at,omitempty"`
+	// RRule и RepeatText — правило повторения в формате RRULE и его описание для человека.
+	// Вычисляются при выдаче задачи и в БД не хранятся.
+	RRule      string `json:"rrule,omitempty"`
+	RepeatText string `json:"repeat_text,omitempty"`
 }
 
 // GetTask возвращает задачу по ID из базы данных.
@@ -22,7 +112,8 @@ func GetTask(id string) (*Task, error) {
 		return nil, errors.New("db.DB is nil: database connection not initialized")
 	}
 
-	query := `SELECT id, date, title, comment, repeat FROM scheduler WHERE id = ?`
+	query := `SELECT id, date, time, title, comment, repeat, priority, list_id FROM scheduler
+	WHERE id = ? AND deleted_at = ''`
 	idInt, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("некорректный формат ID: %w", err) // Ошибка парсинга
@@ -30,9 +121,9 @@ func GetTask(id string) (*Task, error) {
 	row := DB.QueryRow(query, idInt)
 
 	var task Task
-	var dbID int64
+	var dbID, listID int64
 
-	if err := row.Scan(&dbID, &task.Date, &task.Title, &task.Comment, &task.Repeat); err != nil {
+	if err := row.Scan(&dbID, &task.Date, &task.Time, &task.Title, &task.Comment, &task.Repeat, &task.Priority, &listID); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("задача с ID %s не найдена", id)
 		}
@@ -40,14 +131,30 @@ func GetTask(id string) (*Task, error) {
 	}
 
 	task.ID = strconv.FormatInt(dbID, 10)
+	task.ListID = formatListID(listID)
+	if err := fillTags([]*Task{&task}); err != nil {
+		return nil, err
+	}
+	if err := fillProgress([]*Task{&task}); err != nil {
+		return nil, err
+	}
+	if err := fillBlocked([]*Task{&task}); err != nil {
+		return nil, err
+	}
 
 	return &task, nil
 }
 
-// AddTask добавляет новую задачу в базу данных и возвращает её ID.
+// AddTask добавляет новую задачу вместе с её метками в базу данных и возвращает её ID.
 func AddTask(task *Task) (int64, error) {
-	query := `INSERT INTO scheduler (date, title, comment, repeat) VALUES (?, ?, ?, ?)`
-	res, err := DB.Exec(query, task.Date, task.Title, task.Comment, task.Repeat)
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO scheduler (date, time, title, comment, repeat, priority, list_id) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	res, err := tx.Exec(query, task.Date, task.Time, task.Title, task.Comment, task.Repeat, task.Priority, parseListID(task.ListID))
 	if err != nil {
 		return 0, fmt.Errorf("ошибка добавления задачи в БД: %w", err)
 	}
@@ -55,14 +162,47 @@ func AddTask(task *Task) (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("ошибка получения ID последней вставленной записи: %w", err)
 	}
+	if err := setTaskTags(tx, lastID, task.Tags); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
 	task.ID = strconv.FormatInt(lastID, 10)
 	return lastID, nil
 }
 
-// Tasks возвращает список задач из базы данных с ограничением по количеству.
-func Tasks(w http.ResponseWriter, limit int) ([]*Task, error) {
-	query := `SELECT id, date, title, comment, repeat FROM scheduler ORDER BY date LIMIT ?`
-	rows, err := DB.Query(query, limit)
+// Tasks возвращает список задач из базы данных, отобранных по filter, с ограничением по количеству.
+func Tasks(w http.ResponseWriter, limit int, filter TaskFilter) ([]*Task, error) {
+	orderBy, ok := orders[filter.Order]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный порядок сортировки %q", filter.Order)
+	}
+	where := []string{`deleted_at = ''`}
+	if filter.Trashed {
+		where[0] = `deleted_at != ''`
+	}
+	var args []any
+	if len(filter.Tags) > 0 {
+		tagged := `id IN (SELECT tt.task_id FROM task_tags tt JOIN tags t ON t.id = tt.tag_id
+		WHERE t.name IN (` + placeholders(len(filter.Tags)) + `)`
+		for _, name := range filter.Tags {
+			args = append(args, name)
+		}
+		if filter.AllTags {
+			tagged += ` GROUP BY tt.task_id HAVING count(DISTINCT t.id) = ?`
+			args = append(args, len(filter.Tags))
+		}
+		where = append(where, tagged+`)`)
+	}
+	if filter.ListID != "" {
+		where = append(where, `list_id = ?`)
+		args = append(args, parseListID(filter.ListID))
+	}
+
+	query := `SELECT id, date, time, title, comment, repeat, priority, list_id, deleted_at FROM scheduler
+	WHERE ` + strings.Join(where, ` AND `) + ` ORDER BY ` + orderBy + ` LIMIT ?`
+	rows, err := DB.Query(query, append(args, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса к БД: %w", err)
 	}
@@ -71,15 +211,26 @@ func Tasks(w http.ResponseWriter, limit int) ([]*Task, error) {
 	var tasks []*Task
 	for rows.Next() {
 		var task Task
-		if err := rows.Scan(&task.ID, &task.Date, &task.Title, &task.Comment, &task.Repeat); err != nil {
+		var listID int64
+		if err := rows.Scan(&task.ID, &task.Date, &task.Time, &task.Title, &task.Comment, &task.Repeat, &task.Priority, &listID, &task.DeletedAt); err != nil {
 			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
 		}
+		task.ListID = formatListID(listID)
 		tasks = append(tasks, &task)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("ошибка при чтении строк: %w", err)
 	}
+	if err := fillTags(tasks); err != nil {
+		return nil, err
+	}
+	if err := fillProgress(tasks); err != nil {
+		return nil, err
+	}
+	if err := fillBlocked(tasks); err != nil {
+		return nil, err
+	}
 	if len(tasks) == 0 {
 		tasks = []*Task{} // Возвращаем пустой срез, если нет задач
 		return tasks, nil
@@ -88,9 +239,28 @@ func Tasks(w http.ResponseWriter, limit int) ([]*Task, error) {
 }
 
 // UpdateTask обновляет существующую задачу в базе данных по её ID.
+// Метки задачи заменяются, только если task.Tags не nil, а список — только если указан task.ListID.
 func UpdateTask(task *Task) error {
-	query := `UPDATE scheduler SET date = ?, title = ?, comment = ?, repeat = ? WHERE id = ?`
-	res, err := DB.Exec(query, task.Date, task.Title, task.Comment, task.Repeat, task.ID)
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateTask(tx, task); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// updateTask обновляет задачу в транзакции tx; см. UpdateTask.
+func updateTask(tx *sql.Tx, task *Task) error {
+	query := `UPDATE scheduler SET date = ?, time = ?, title = ?, comment = ?, repeat = ?, priority = ?
+	WHERE id = ? AND deleted_at = ''`
+	res, err := tx.Exec(query, task.Date, task.Time, task.Title, task.Comment, task.Repeat, task.Priority, task.ID)
 	if err != nil {
 		return err
 	}
@@ -102,19 +272,51 @@ func UpdateTask(task *Task) error {
 	if count == 0 {
 		return fmt.Errorf(`incorrect id for updating task`)
 	}
+	if task.ListID != "" {
+		if _, err := tx.Exec(`UPDATE scheduler SET list_id = ? WHERE id = ?`, parseListID(task.ListID), task.ID); err != nil {
+			return fmt.Errorf("ошибка переноса задачи в другой список: %w", err)
+		}
+	}
+	if task.Tags != nil {
+		idInt, err := strconv.ParseInt(task.ID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("некорректный формат ID: %w", err)
+		}
+		if err := setTaskTags(tx, idInt, task.Tags); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// DeleteTask удаляет задачу из базы данных по её ID.
+// DeleteTask перемещает задачу в корзину по её ID. Даты-исключения, метки и чек-лист задачи
+// сохраняются, чтобы её можно было восстановить; окончательно задача удаляется функцией PurgeTask.
 func DeleteTask(id string) error {
-	query := `DELETE FROM scheduler WHERE id = ?`
+	tx, err := DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := trashTask(tx, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return nil
+}
+
+// trashTask перемещает задачу в корзину в транзакции tx; см. DeleteTask.
+func trashTask(tx *sql.Tx, id string) error {
 	idInt, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
 		return fmt.Errorf("некорректный формат ID: %w", err) // Ошибка парсинга
 	}
-	res, err := DB.Exec(query, idInt)
+	deletedAt := time.Now().UTC().Format(time.RFC3339)
+	res, err := tx.Exec(`UPDATE scheduler SET deleted_at = ? WHERE id = ? AND deleted_at = ''`, deletedAt, idInt)
 	if err != nil {
-		return fmt.Errorf("ошибка удаления задачи из БД: %w", err)
+		return fmt.Errorf("ошибка перемещения задачи в корзину: %w", err)
 	}
 	// Проверяем, что удаление затронуло хотя бы одну строку
 	count, err := res.RowsAffected()
@@ -126,3 +328,47 @@ func DeleteTask(id string) error {
 	}
 	return nil
 }
+
+// deleteTasks удаляет задачи, отобранные условием cond, вместе с их датами-исключениями,
+// метками, чек-листами и зависимостями и возвращает количество удалённых задач.
+func deleteTasks(tx *sql.Tx, cond string, args ...any) (int64, error) {
+	ids := `SELECT id FROM scheduler WHERE ` + cond
+	if _, err := tx.Exec(`DELETE FROM exceptions WHERE task_id IN (`+ids+`)`, args...); err != nil {
+		return 0, fmt.Errorf("ошибка удаления дат-исключений задачи из БД: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id IN (`+ids+`)`, args...); err != nil {
+		return 0, fmt.Errorf("ошибка удаления меток задачи из БД: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM subtasks WHERE task_id IN (`+ids+`)`, args...); err != nil {
+		return 0, fmt.Errorf("ошибка удаления чек-листа задачи из БД: %w", err)
+	}
+	// Удалённая задача больше не блокирует другие задачи и не зависит от них.
+	query := `DELETE FROM dependencies WHERE task_id IN (` + ids + `) OR blocker_id IN (` + ids + `)`
+	if _, err := tx.Exec(query, append(args, args...)...); err != nil {
+		return 0, fmt.Errorf("ошибка удаления зависимостей задачи из БД: %w", err)
+	}
+	res, err := tx.Exec(`DELETE FROM scheduler WHERE `+cond, args...)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления задачи из БД: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	return count, nil
+}
+
+// parseListID преобразует ID списка в значение столбца list_id; пустой и некорректный ID
+// означают «Входящие». Существование списка проверяется до сохранения задачи.
+func parseListID(id string) int64 {
+	listID, _ := strconv.ParseInt(id, 10, 64)
+	return listID
+}
+
+// formatListID преобразует значение столбца list_id в ID списка задачи.
+func formatListID(listID int64) string {
+	if listID == 0 {
+		return ""
+	}
+	return strconv.FormatInt(listID, 10)
+}
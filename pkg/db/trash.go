@@ -0,0 +1,76 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TrashedTasks возвращает задачи из корзины, начиная с удалённых последними.
+func TrashedTasks(limit int) ([]*Task, error) {
+	return Tasks(nil, limit, TaskFilter{Order: orderDeleted, Trashed: true})
+}
+
+// RestoreTask возвращает задачу из корзины по её ID.
+func RestoreTask(id string) error {
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	res, err := DB.Exec(`UPDATE scheduler SET deleted_at = '' WHERE id = ? AND deleted_at != ''`, idInt)
+	if err != nil {
+		return fmt.Errorf("ошибка восстановления задачи из корзины: %w", err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества затронутых строк: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("задача с ID %s не найдена в корзине", id)
+	}
+	return nil
+}
+
+// PurgeTask окончательно удаляет задачу из корзины вместе с её датами-исключениями,
+// метками, чек-листом и зависимостями.
+func PurgeTask(id string) error {
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("некорректный формат ID: %w", err)
+	}
+	count, err := purge(`id = ? AND deleted_at != ''`, idInt)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("задача с ID %s не найдена в корзине", id)
+	}
+	return nil
+}
+
+// PurgeTrash окончательно удаляет задачи, перемещённые в корзину раньше before
+// (время в формате RFC 3339). Пустой before очищает корзину целиком.
+// Возвращает количество удалённых задач.
+func PurgeTrash(before string) (int64, error) {
+	if before == "" {
+		return purge(`deleted_at != ''`)
+	}
+	return purge(`deleted_at != '' AND deleted_at < ?`, before)
+}
+
+// purge окончательно удаляет задачи, отобранные условием cond, в одной транзакции.
+func purge(cond string, args ...any) (int64, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	count, err := deleteTasks(tx, cond, args...)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,120 @@
+package repeat
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Calendar сообщает, является ли дата нерабочим праздничным днём.
+type Calendar interface {
+	IsHoliday(date time.Time) bool
+}
+
+// noHolidays — календарь без праздничных дней.
+type noHolidays struct{}
+
+func (noHolidays) IsHoliday(time.Time) bool { return false }
+
+// Holidays — календарь праздников, который учитывают правила рабочих дней b и bm.
+// По умолчанию праздников нет, выходными считаются только суббота и воскресенье.
+var Holidays Calendar = noHolidays{}
+
+// isWorkday возвращает true, если date — рабочий день: не суббота, не воскресенье и не праздник.
+func isWorkday(date time.Time) bool {
+	return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday && !Holidays.IsHoliday(date)
+}
+
+// BusinessDaily — правило "b <число>": повторение через заданное число рабочих дней.
+type BusinessDaily struct {
+	Interval int
+}
+
+func parseBusinessDaily(args []string) (Rule, error) {
+	if err := checkArgs("b", args, 1, 1, "не указан интервал в рабочих днях"); err != nil {
+		return nil, err
+	}
+	interval, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, argError(0, CodeNotNumber, "b: интервал в рабочих днях должен быть числом")
+	}
+	if interval <= 0 || interval > 400 {
+		return nil, argError(0, CodeOutOfRange, "b %d: превышен максимально допустимый интервал (1-400)", interval)
+	}
+	return BusinessDaily{Interval: interval}, nil
+}
+
+func (r BusinessDaily) Next(after time.Time) time.Time {
+	date := after
+	for left := r.Interval; left > 0; left-- {
+		date = searchDay(date, isWorkday)
+		if date.IsZero() {
+			return date
+		}
+	}
+	return date
+}
+
+func (r BusinessDaily) String() string {
+	return fmt.Sprintf("b %d", r.Interval)
+}
+
+// BusinessMonthly — правило "bm <номера>": повторение в рабочие дни месяца с указанными номерами.
+// Отрицательные номера отсчитываются от конца месяца: -1 — последний рабочий день.
+type BusinessMonthly struct {
+	Days []int
+}
+
+func parseBusinessMonthly(args []string) (Rule, error) {
+	if err := checkArgs("bm", args, 1, 1, "не указаны номера рабочих дней месяца"); err != nil {
+		return nil, err
+	}
+	days, err := parseList(args[0], -23, 23)
+	if err != nil {
+		return nil, listError(0, err, "bm: некорректный список номеров рабочих дней")
+	}
+	sortList(days)
+	return BusinessMonthly{Days: days}, nil
+}
+
+func (r BusinessMonthly) Next(after time.Time) time.Time {
+	limit := after.AddDate(0, 0, maxSearchDays)
+	// Рабочие дни перебираются помесячно: номер рабочего дня считается по списку рабочих дней
+	// месяца, который строится один раз на месяц, а не заново для каждого дня.
+	month := time.Date(after.Year(), after.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for ; !month.After(limit); month = month.AddDate(0, 1, 0) {
+		workdays := monthWorkdays(month)
+		for i, day := range workdays {
+			// Как и searchDay, сохраняем время суток after.
+			date := time.Date(day.Year(), day.Month(), day.Day(),
+				after.Hour(), after.Minute(), after.Second(), after.Nanosecond(), after.Location())
+			if !date.After(after) {
+				continue
+			}
+			if date.After(limit) {
+				return time.Time{}
+			}
+			if slices.Contains(r.Days, i+1) || slices.Contains(r.Days, i-len(workdays)) {
+				return date
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// monthWorkdays возвращает рабочие дни месяца, которому принадлежит month, по возрастанию.
+func monthWorkdays(month time.Time) []time.Time {
+	var workdays []time.Time
+	for day := 1; day <= daysInMonth(month); day++ {
+		date := time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC)
+		if isWorkday(date) {
+			workdays = append(workdays, date)
+		}
+	}
+	return workdays
+}
+
+func (r BusinessMonthly) String() string {
+	return "bm " + joinList(r.Days)
+}
@@ -0,0 +1,309 @@
+package repeat
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Языки, на которых Describe умеет описывать правила.
+const (
+	LangRU = "ru"
+	LangEN = "en"
+)
+
+// Названия месяцев и дней недели. Индекс 0 соответствует январю и понедельнику.
+var (
+	monthsRU = []string{"январь", "февраль", "март", "апрель", "май", "июнь",
+		"июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"}
+	monthsGenitiveRU = []string{"января", "февраля", "марта", "апреля", "мая", "июня",
+		"июля", "августа", "сентября", "октября", "ноября", "декабря"}
+	monthsEN = []string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"}
+
+	weekdaysRU       = []string{"понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье"}
+	weekdaysDativeRU = []string{"понедельникам", "вторникам", "средам", "четвергам", "пятницам", "субботам", "воскресеньям"}
+	weekdaysEN       = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+	// weekdayGenderRU — грамматический род дня недели: 0 — мужской, 1 — женский, 2 — средний.
+	weekdayGenderRU = []int{0, 0, 1, 0, 1, 1, 2}
+
+	// ordinalsRU — порядковые числительные в мужском, женском и среднем роде.
+	ordinalsRU = [][3]string{
+		{"первый", "первая", "первое"},
+		{"второй", "вторая", "второе"},
+		{"третий", "третья", "третье"},
+		{"четвёртый", "четвёртая", "четвёртое"},
+		{"пятый", "пятая", "пятое"},
+	}
+	lastRU     = [3]string{"последний", "последняя", "последнее"}
+	ordinalsEN = []string{"first", "second", "third", "fourth", "fifth"}
+)
+
+// Describe возвращает описание правила повторения на языке lang (LangRU или LangEN),
+// например "каждые 30 дней" или "every 30 days". Для неизвестного языка используется русский.
+func Describe(rule Rule, lang string) string {
+	en := lang == LangEN
+	switch r := rule.(type) {
+	case Daily:
+		if en {
+			return everyEN(r.Interval, "day", "days")
+		}
+		return everyRU(r.Interval, "день", "дня", "дней")
+	case BusinessDaily:
+		if en {
+			return everyEN(r.Interval, "working day", "working days")
+		}
+		return everyRU(r.Interval, "рабочий день", "рабочих дня", "рабочих дней")
+	case SubDaily:
+		if r.Step%time.Hour == 0 {
+			if en {
+				return everyEN(int(r.Step/time.Hour), "hour", "hours")
+			}
+			return everyRU(int(r.Step/time.Hour), "час", "часа", "часов")
+		}
+		minutes := int(r.Step / time.Minute)
+		if en {
+			return everyEN(minutes, "minute", "minutes")
+		}
+		return pluralRU(minutes, "каждую минуту", "каждую %d минуту", "каждые %d минуты", "каждые %d минут")
+	case Yearly:
+		return describeYearly(r, en)
+	case Monthly:
+		return describeMonthly(r, en)
+	case Weekly:
+		days := make([]string, len(r.Days))
+		for i, day := range r.Days {
+			if en {
+				days[i] = weekdaysEN[day-1]
+			} else {
+				days[i] = weekdaysDativeRU[day-1]
+			}
+		}
+		if en {
+			return "every " + joinWords(days, "and")
+		}
+		return "по " + joinWords(days, "и")
+	case MonthlyWeekday:
+		var days []string
+		for _, ordinal := range r.Ordinals {
+			for _, day := range r.Weekdays {
+				if en {
+					days = append(days, "the "+ordinalEN(ordinal)+" "+weekdaysEN[day-1])
+				} else {
+					days = append(days, ordinalRU(ordinal, weekdayGenderRU[day-1])+" "+weekdaysRU[day-1])
+				}
+			}
+		}
+		if en {
+			return "every month on " + joinWords(days, "and") + monthsSuffix(r.Months, en)
+		}
+		return "каждый месяц: " + joinWords(days, "и") + monthsSuffix(r.Months, en)
+	case BusinessMonthly:
+		days := make([]string, len(r.Days))
+		for i, day := range r.Days {
+			if en {
+				days[i] = "the " + ordinalEN(day) + " working day"
+			} else {
+				days[i] = ordinalRU(day, 0) + " рабочий день"
+			}
+		}
+		if en {
+			return "every month on " + joinWords(days, "and")
+		}
+		return "каждый месяц: " + joinWords(days, "и")
+	case Series:
+		return describeSeries(r, en)
+	}
+	return rule.String()
+}
+
+// describeYearly описывает правило y.
+func describeYearly(r Yearly, en bool) string {
+	if len(r.Dates) == 0 {
+		if en {
+			return "every year"
+		}
+		return "каждый год"
+	}
+	dates := make([]string, len(r.Dates))
+	for i, mmdd := range r.Dates {
+		if en {
+			dates[i] = fmt.Sprintf("%s %d", monthsEN[mmdd/100-1], mmdd%100)
+		} else {
+			dates[i] = fmt.Sprintf("%d %s", mmdd%100, monthsGenitiveRU[mmdd/100-1])
+		}
+	}
+	var s string
+	if en {
+		s = "every year on " + joinWords(dates, "and")
+	} else {
+		s = "каждый год " + joinWords(dates, "и")
+	}
+	if slices.Contains(r.Dates, 229) {
+		switch {
+		case en && r.Feb28:
+			s += " (February 28 in non-leap years)"
+		case en:
+			s += " (March 1 in non-leap years)"
+		case r.Feb28:
+			s += " (в невисокосный год — 28 февраля)"
+		default:
+			s += " (в невисокосный год — 1 марта)"
+		}
+	}
+	return s
+}
+
+// describeMonthly описывает правило m.
+func describeMonthly(r Monthly, en bool) string {
+	days := make([]string, len(r.Days))
+	for i, day := range r.Days {
+		switch {
+		case day > 0:
+			days[i] = fmt.Sprint(day)
+		case en:
+			days[i] = ordinalEN(day) + " day"
+		default:
+			days[i] = ordinalRU(day, 2) + " число"
+		}
+	}
+	if en {
+		return "every month on day " + joinWords(days, "and") + monthsSuffix(r.Months, en)
+	}
+	return "каждый месяц: " + joinWords(days, "и") + monthsSuffix(r.Months, en)
+}
+
+// describeSeries описывает правило с модификаторами серии.
+func describeSeries(r Series, en bool) string {
+	parts := []string{Describe(r.Rule, langOf(en))}
+	if !r.Until.IsZero() {
+		if en {
+			parts = append(parts, "until "+r.Until.Format("2006-01-02"))
+		} else {
+			parts = append(parts, "по "+r.Until.Format("02.01.2006"))
+		}
+	}
+	if r.Count > 0 {
+		if en && r.Count == 1 {
+			parts = append(parts, "1 occurrence left")
+		} else if en {
+			parts = append(parts, fmt.Sprintf("%d occurrences left", r.Count))
+		} else {
+			parts = append(parts, fmt.Sprintf("осталось повторений: %d", r.Count))
+		}
+	}
+	if r.AfterDone {
+		if en {
+			parts = append(parts, "counting from completion")
+		} else {
+			parts = append(parts, "считая от даты выполнения")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// langOf возвращает код языка по признаку en.
+func langOf(en bool) string {
+	if en {
+		return LangEN
+	}
+	return LangRU
+}
+
+// monthsSuffix описывает фильтр по месяцам. Для пустого списка возвращает пустую строку.
+func monthsSuffix(months []int, en bool) string {
+	if len(months) == 0 {
+		return ""
+	}
+	names := make([]string, len(months))
+	for i, month := range months {
+		if en {
+			names[i] = monthsEN[month-1]
+		} else {
+			names[i] = monthsRU[month-1]
+		}
+	}
+	if en {
+		return " in " + joinWords(names, "and")
+	}
+	return " (месяцы: " + strings.Join(names, ", ") + ")"
+}
+
+// everyEN формирует английскую фразу вида "every day" или "every 3 days".
+func everyEN(n int, one, many string) string {
+	if n == 1 {
+		return "every " + one
+	}
+	return fmt.Sprintf("every %d %s", n, many)
+}
+
+// everyRU формирует русскую фразу вида "каждый день", "каждые 3 дня" или "каждые 5 дней".
+func everyRU(n int, one, few, many string) string {
+	return pluralRU(n, "каждый "+one, "каждый %d "+one, "каждые %d "+few, "каждые %d "+many)
+}
+
+// pluralRU выбирает форму фразы для числа n по правилам русского языка.
+// Формы first (для n == 1), one, few и many могут содержать %d для подстановки числа.
+func pluralRU(n int, first, one, few, many string) string {
+	switch {
+	case n == 1:
+		return first
+	case n%10 == 1 && n%100 != 11:
+		return fmt.Sprintf(one, n)
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return fmt.Sprintf(few, n)
+	}
+	return fmt.Sprintf(many, n)
+}
+
+// ordinalRU возвращает русское порядковое числительное для n в роде gender (0 — м., 1 — ж., 2 — ср.).
+// Отрицательные n отсчитываются от конца: -1 — «последний», -2 — «второй с конца».
+func ordinalRU(n int, gender int) string {
+	if n == -1 {
+		return lastRU[gender]
+	}
+	suffix := ""
+	if n < 0 {
+		n, suffix = -n, " с конца"
+	}
+	if n <= len(ordinalsRU) {
+		return ordinalsRU[n-1][gender] + suffix
+	}
+	return fmt.Sprintf("%d-%s", n, [3]string{"й", "я", "е"}[gender]) + suffix
+}
+
+// ordinalEN возвращает английское порядковое числительное для n.
+// Отрицательные n отсчитываются от конца: -1 — "last", -2 — "second to last".
+func ordinalEN(n int) string {
+	if n == -1 {
+		return "last"
+	}
+	if n < 0 {
+		return ordinalEN(-n) + " to last"
+	}
+	if n <= len(ordinalsEN) {
+		return ordinalsEN[n-1]
+	}
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// joinWords объединяет слова через запятую, а последние два — через союз conj.
+func joinWords(words []string, conj string) string {
+	if len(words) <= 1 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " " + conj + " " + words[len(words)-1]
+}
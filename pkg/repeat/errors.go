@@ -0,0 +1,134 @@
+package repeat
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Коды ошибок разбора правил повторения. По коду клиент может сопоставить ошибку
+// с полем формы и показать сообщение на своём языке.
+const (
+	CodeEmpty           = "empty"            // правило — пустая строка
+	CodeUnknownRule     = "unknown_rule"     // неизвестное обозначение правила
+	CodeMissingValue    = "missing_value"    // не указан обязательный аргумент
+	CodeExtraArgument   = "extra_argument"   // лишний аргумент правила
+	CodeNotNumber       = "not_a_number"     // ожидалось число
+	CodeOutOfRange      = "out_of_range"     // число вне допустимого диапазона
+	CodeInvalidDate     = "invalid_date"     // некорректная дата
+	CodeInvalidValue    = "invalid_value"    // некорректное значение другого вида
+	CodeNeverOccurs     = "never_occurs"     // правило не даёт ни одной даты
+	CodeDuplicate       = "duplicate"        // модификатор или параметр указан несколько раз
+	CodeUnknownModifier = "unknown_modifier" // неизвестный модификатор правила
+	CodeUnsupported     = "unsupported"      // RRULE, которое нельзя выразить правилами
+)
+
+// ParseError — ошибка разбора правила повторения с указанием места ошибки.
+type ParseError struct {
+	// Code — машиночитаемый код ошибки, одна из констант Code*.
+	Code string `json:"code"`
+	// Token — фрагмент правила, в котором обнаружена ошибка; пустой, если аргумент не указан.
+	Token string `json:"token"`
+	// Pos — позиция ошибки в строке правила в символах, начиная с 0. Для отсутствующего
+	// аргумента указывает на конец правила.
+	Pos int `json:"pos"`
+	// Message — описание ошибки на русском языке.
+	Message string `json:"message"`
+	// Hint — подсказка, как записать правило правильно.
+	Hint string `json:"hint,omitempty"`
+
+	// arg — номер аргумента, к которому относится ошибка; пока ошибка не привязана
+	// к строке правила, Pos отсчитывается от начала этого аргумента.
+	arg int
+}
+
+func (e *ParseError) Error() string {
+	return e.Message
+}
+
+// usage содержит подсказки по записи каждого вида правил.
+var usage = map[string]string{
+	"d":   "d <интервал в днях 1-400>, например d 7",
+	"y":   "y [<даты MMDD через запятую> [feb28|mar1]], например y или y 0315,1225",
+	"m":   "m <дни месяца 1-31, -1, -2> [<месяцы 1-12>], например m 1,15 или m -1 1,7",
+	"w":   "w <дни недели 1-7>, например w 1,3,5",
+	"wm":  "wm <порядковые номера -5..5> <дни недели 1-7> [<месяцы 1-12>], например wm 2 2",
+	"b":   "b <интервал в рабочих днях 1-400>, например b 1",
+	"bm":  "bm <номера рабочих дней -23..23>, например bm 1,-1",
+	"h":   "h <интервал в часах 1-24>, например h 2",
+	"min": "min <интервал в минутах 1-1440>, например min 30",
+}
+
+// rulesHint перечисляет виды правил для подсказки к неизвестному правилу.
+const rulesHint = "правило начинается с d, y, m, w, wm, b, bm, h или min, либо записывается в формате RRULE, например FREQ=WEEKLY;BYDAY=MO"
+
+// argError создаёт ошибку, относящуюся к аргументу arg правила.
+func argError(arg int, code string, format string, a ...any) *ParseError {
+	return &ParseError{Code: code, Message: fmt.Sprintf(format, a...), arg: arg}
+}
+
+// checkArgs проверяет, что у правила name от min до max аргументов.
+func checkArgs(name string, args []string, min, max int, missing string) error {
+	if len(args) < min {
+		return argError(len(args), CodeMissingValue, "%s: %s", name, missing)
+	}
+	if len(args) > max {
+		return argError(max, CodeExtraArgument, "%s: лишний аргумент %q", name, args[max])
+	}
+	return nil
+}
+
+// listError относит ошибку разбора списка к аргументу arg и дополняет её сообщение.
+func listError(arg int, err error, format string, a ...any) error {
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		return fmt.Errorf(format+": %w", append(a, err)...)
+	}
+	perr.arg = arg
+	perr.Message = fmt.Sprintf(format, a...) + ": " + perr.Message
+	return perr
+}
+
+// locate привязывает ошибку разбора аргументов к строке правила s: arg ошибки отсчитывается
+// от токена first, а positions содержит байтовые смещения токенов в s.
+func locate(err error, s string, tokens []string, positions []int, first int, hint string) error {
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		return err
+	}
+	i := first + perr.arg
+	offset := len(strings.TrimRightFunc(s, unicode.IsSpace))
+	if i < len(tokens) {
+		if perr.Token == "" {
+			perr.Token = tokens[i]
+		}
+		offset = positions[i] + perr.Pos
+	}
+	perr.Pos = utf8.RuneCountInString(s[:offset])
+	if perr.Hint == "" {
+		perr.Hint = hint
+	}
+	return perr
+}
+
+// fields разбивает s на токены по пробельным символам, как strings.Fields,
+// и возвращает также байтовое смещение каждого токена.
+func fields(s string) ([]string, []int) {
+	var tokens []string
+	var positions []int
+	start := -1
+	for i, r := range s + " " {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				tokens = append(tokens, s[start:i])
+				positions = append(positions, start)
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return tokens, positions
+}
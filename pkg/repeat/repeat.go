@@ -0,0 +1,147 @@
+// Package repeat разбирает правила повторения задач и вычисляет даты повторений.
+package repeat
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule представляет собой разобранное правило повторения задачи.
+type Rule interface {
+	// Next возвращает ближайшую дату повторения, которая строго больше after.
+	// Нулевое время означает, что подходящей даты не существует.
+	Next(after time.Time) time.Time
+	// String возвращает правило в каноническом виде, пригодном для хранения в БД.
+	String() string
+}
+
+// parseFunc разбирает аргументы правила, следующие за его обозначением.
+type parseFunc func(args []string) (Rule, error)
+
+// parsers сопоставляет обозначение правила с функцией его разбора.
+// Новые виды правил подключаются добавлением записи в эту таблицу.
+var parsers = map[string]parseFunc{
+	"d":   parseDaily,
+	"y":   parseYearly,
+	"m":   parseMonthly,
+	"w":   parseWeekly,
+	"wm":  parseMonthlyWeekday,
+	"b":   parseBusinessDaily,
+	"bm":  parseBusinessMonthly,
+	"h":   parseHourly,
+	"min": parseMinutely,
+}
+
+// maxSearchDays ограничивает перебор дней при поиске даты по календарным правилам.
+const maxSearchDays = 366 * 8
+
+// Parse разбирает строку правила повторения. Помимо собственного формата правил
+// принимается правило в формате RRULE (RFC 5545), см. FromRRULE.
+// Ошибки разбора возвращаются в виде *ParseError.
+func Parse(s string) (Rule, error) {
+	if IsRRULE(s) {
+		return FromRRULE(s)
+	}
+	parts, positions := fields(s)
+	if len(parts) == 0 {
+		return nil, &ParseError{Code: CodeEmpty, Message: "в параметре repeat — пустая строка", Hint: rulesHint}
+	}
+	parse, ok := parsers[parts[0]]
+	if !ok {
+		return nil, locate(argError(0, CodeUnknownRule, "указан неверный формат repeat: %s", s), s, parts, positions, 0, rulesHint)
+	}
+
+	// Аргументы базового правила заканчиваются на первом модификаторе.
+	end := 1
+	for end < len(parts) && !isModifier(parts[end]) {
+		end++
+	}
+	rule, err := parse(parts[1:end])
+	if err != nil {
+		return nil, locate(err, s, parts, positions, 1, usage[parts[0]])
+	}
+	if end == len(parts) {
+		return rule, nil
+	}
+	rule, err = parseSeries(rule, parts[end:])
+	if err != nil {
+		return nil, locate(err, s, parts, positions, end, seriesHint)
+	}
+	return rule, nil
+}
+
+// parseList разбирает список чисел через запятую и проверяет, что каждое
+// из них лежит в диапазоне [min, max] и не равно нулю. Повторы отбрасываются.
+// В ошибке указывается смещение некорректного элемента от начала s.
+func parseList(s string, min, max int) ([]int, error) {
+	seen := map[int]bool{}
+	var values []int
+	offset := 0
+	for _, item := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, &ParseError{Code: CodeNotNumber, Token: item, Pos: offset, Message: fmt.Sprintf("%q не является числом", item)}
+		}
+		if n == 0 || n < min || n > max {
+			return nil, &ParseError{Code: CodeOutOfRange, Token: item, Pos: offset, Message: fmt.Sprintf("значение %d вне допустимого диапазона", n)}
+		}
+		if !seen[n] {
+			seen[n] = true
+			values = append(values, n)
+		}
+		offset += len(item) + 1
+	}
+	return values, nil
+}
+
+// sortList упорядочивает список: сначала положительные значения по возрастанию,
+// затем отрицательные от -1 вглубь (-1, -2, ...).
+func sortList(values []int) {
+	sort.Slice(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if (a > 0) != (b > 0) {
+			return a > 0
+		}
+		if a > 0 {
+			return a < b
+		}
+		return a > b
+	})
+}
+
+// joinList собирает список чисел в строку через запятую.
+func joinList(values []int) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = strconv.Itoa(v)
+	}
+	return strings.Join(items, ",")
+}
+
+// isoWeekday возвращает номер дня недели, где 1 — понедельник, 7 — воскресенье.
+func isoWeekday(date time.Time) int {
+	if date.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(date.Weekday())
+}
+
+// daysInMonth возвращает количество дней в месяце, которому принадлежит date.
+func daysInMonth(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// searchDay перебирает дни после after и возвращает первый, для которого match вернул true.
+func searchDay(after time.Time, match func(date time.Time) bool) time.Time {
+	date := after
+	for i := 0; i < maxSearchDays; i++ {
+		date = date.AddDate(0, 0, 1)
+		if match(date) {
+			return date
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,402 @@
+package repeat
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// rruleDays — обозначения дней недели в RRULE в порядке номеров правила w (1 — понедельник).
+var rruleDays = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// IsRRULE возвращает true, если строка похожа на правило в формате RRULE (RFC 5545).
+func IsRRULE(s string) bool {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	return strings.HasPrefix(s, "RRULE:") || strings.HasPrefix(s, "FREQ=")
+}
+
+// ToRRULE преобразует правило повторения задачи с датой start в строку RRULE (RFC 5545).
+// Для правил, которые нельзя точно выразить в RRULE, возвращается ошибка.
+// Нулевое start означает, что дата задачи неизвестна.
+func ToRRULE(rule Rule, start time.Time) (string, error) {
+	switch r := rule.(type) {
+	case Daily:
+		if r.Interval == 1 {
+			return "FREQ=DAILY", nil
+		}
+		return fmt.Sprintf("FREQ=DAILY;INTERVAL=%d", r.Interval), nil
+	case SubDaily:
+		if r.Step%time.Hour == 0 {
+			return fmt.Sprintf("FREQ=HOURLY;INTERVAL=%d", r.Step/time.Hour), nil
+		}
+		return fmt.Sprintf("FREQ=MINUTELY;INTERVAL=%d", r.Step/time.Minute), nil
+	case Yearly:
+		// В RRULE 29 февраля в невисокосный год пропускается, что не совпадает ни с feb28, ни с mar1.
+		// Задача на 29 февраля без списка дат переходит на 1 марта и дальше повторяется 1 марта.
+		if len(r.Dates) == 0 && isLeapDay(start) {
+			return "", errors.New("ежегодное правило для задачи на 29 февраля не может быть представлено в формате RRULE")
+		}
+		if len(r.Dates) == 0 {
+			return "FREQ=YEARLY", nil
+		}
+		if slices.Contains(r.Dates, 229) {
+			return "", errors.New("правило с 29 февраля не может быть представлено в формате RRULE")
+		}
+		var months, days []int
+		for _, mmdd := range r.Dates {
+			if !slices.Contains(months, mmdd/100) {
+				months = append(months, mmdd/100)
+			}
+			if !slices.Contains(days, mmdd%100) {
+				days = append(days, mmdd%100)
+			}
+		}
+		if len(months)*len(days) != len(r.Dates) {
+			return "", fmt.Errorf("правило %s не может быть представлено в формате RRULE", rule)
+		}
+		slices.Sort(days)
+		return "FREQ=YEARLY;BYMONTH=" + joinList(months) + ";BYMONTHDAY=" + joinList(days), nil
+	case Monthly:
+		s := "FREQ=MONTHLY;BYMONTHDAY=" + joinList(r.Days)
+		if len(r.Months) > 0 {
+			s += ";BYMONTH=" + joinList(r.Months)
+		}
+		return s, nil
+	case MonthlyWeekday:
+		var days []string
+		for _, ordinal := range r.Ordinals {
+			for _, day := range r.Weekdays {
+				days = append(days, strconv.Itoa(ordinal)+rruleDays[day-1])
+			}
+		}
+		s := "FREQ=MONTHLY;BYDAY=" + strings.Join(days, ",")
+		if len(r.Months) > 0 {
+			s += ";BYMONTH=" + joinList(r.Months)
+		}
+		return s, nil
+	case Weekly:
+		days := make([]string, len(r.Days))
+		for i, day := range r.Days {
+			days[i] = rruleDays[day-1]
+		}
+		return "FREQ=WEEKLY;BYDAY=" + strings.Join(days, ","), nil
+	case Series:
+		if r.AfterDone {
+			return "", errors.New("правило с отсчётом от даты выполнения не может быть представлено в формате RRULE")
+		}
+		s, err := ToRRULE(r.Rule, start)
+		if err != nil {
+			return "", err
+		}
+		if !r.Until.IsZero() {
+			s += ";UNTIL=" + r.Until.Format("20060102")
+		}
+		if r.Count > 0 {
+			s += ";COUNT=" + strconv.Itoa(r.Count)
+		}
+		return s, nil
+	}
+	return "", fmt.Errorf("правило %s не может быть представлено в формате RRULE", rule)
+}
+
+// CheckRRULEStart проверяет, что правило rule, разобранное из строки s в формате RRULE,
+// точно повторяет RRULE для задачи с датой start. FREQ=YEARLY без BYMONTH и BYMONTHDAY
+// для задачи на 29 февраля пропускает невисокосные годы, а правило y переходит на 1 марта,
+// поэтому такое правило отклоняется с ошибкой *ParseError. Правила не в формате RRULE
+// не проверяются.
+func CheckRRULEStart(s string, rule Rule, start time.Time) error {
+	if !IsRRULE(s) || !isLeapDay(start) {
+		return nil
+	}
+	if series, ok := rule.(Series); ok {
+		rule = series.Rule
+	}
+	if yearly, ok := rule.(Yearly); !ok || len(yearly.Dates) > 0 {
+		return nil
+	}
+	perr := rruleError("FREQ", CodeUnsupported, "FREQ=YEARLY без BYMONTH и BYMONTHDAY для задачи на 29 февраля не поддерживается: "+
+		"в невисокосные годы RRULE пропускает повторение")
+	perr.Token, perr.Pos = rruleToken(s, perr.Token)
+	perr.Hint = "укажите дату задачи, отличную от 29 февраля, или правило y 0229 feb28 либо y 0229 mar1"
+	return perr
+}
+
+// isLeapDay возвращает true, если date — 29 февраля.
+func isLeapDay(date time.Time) bool {
+	return date.Month() == time.February && date.Day() == 29
+}
+
+// rruleHint — подсказка по записи правил в формате RRULE.
+const rruleHint = "поддерживаются FREQ=DAILY, HOURLY, MINUTELY, WEEKLY, MONTHLY и YEARLY с параметрами " +
+	"INTERVAL, BYDAY, BYMONTHDAY, BYMONTH, UNTIL и COUNT, например FREQ=MONTHLY;BYDAY=2TU"
+
+// FromRRULE разбирает строку RRULE (RFC 5545) и преобразует её в правило повторения.
+// Поддерживается только то подмножество RRULE, которое точно выражается собственными правилами;
+// для остальных правил возвращается ошибка *ParseError, а не приближённое правило.
+func FromRRULE(s string) (Rule, error) {
+	rule, err := fromRRULE(s)
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		perr.Token, perr.Pos = rruleToken(s, perr.Token)
+		perr.Hint = rruleHint
+	}
+	return rule, err
+}
+
+// rruleError создаёт ошибку разбора RRULE, относящуюся к параметру key.
+// Пустой key означает, что ошибка относится ко всему правилу.
+func rruleError(key, code, format string, a ...any) *ParseError {
+	return &ParseError{Code: code, Token: key, Message: "RRULE: " + fmt.Sprintf(format, a...)}
+}
+
+// errorCode возвращает код ошибки разбора или CodeInvalidValue для прочих ошибок.
+func errorCode(err error) string {
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		return perr.Code
+	}
+	return CodeInvalidValue
+}
+
+// rruleToken находит в строке RRULE часть с параметром key и возвращает её вместе
+// с позицией в символах. Если key пуст или не найден, ошибка относится ко всему правилу.
+func rruleToken(s, key string) (string, int) {
+	if key == "" {
+		return "", 0
+	}
+	offset := 0
+	for _, part := range strings.Split(s, ";") {
+		token := strings.TrimSpace(part)
+		start := offset + strings.Index(part, token)
+		// Первая часть может начинаться с префикса RRULE:.
+		if len(token) >= len("RRULE:") && strings.EqualFold(token[:len("RRULE:")], "RRULE:") {
+			start += len("RRULE:")
+			token = token[len("RRULE:"):]
+		}
+		name, _, _ := strings.Cut(token, "=")
+		if strings.EqualFold(name, key) || token == key {
+			return token, utf8.RuneCountInString(s[:start])
+		}
+		offset += len(part) + 1
+	}
+	return "", 0
+}
+
+// fromRRULE преобразует RRULE в правило; ошибки относятся к параметрам по их именам.
+func fromRRULE(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToUpper(s), "RRULE:") {
+		s = s[len("RRULE:"):]
+	}
+
+	params := map[string]string{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || key == "" || value == "" {
+			return nil, rruleError(part, CodeInvalidValue, "некорректная часть правила %q", part)
+		}
+		key = strings.ToUpper(key)
+		if _, dup := params[key]; dup {
+			return nil, rruleError(key, CodeDuplicate, "параметр %s указан несколько раз", key)
+		}
+		params[key] = strings.ToUpper(value)
+	}
+
+	interval := 1
+	if value, ok := params["INTERVAL"]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, rruleError("INTERVAL", CodeOutOfRange, "INTERVAL должен быть положительным числом")
+		}
+		interval = n
+	}
+
+	var base string
+	switch freq := params["FREQ"]; freq {
+	case "DAILY":
+		if err := onlyParams(params, "FREQ", "INTERVAL"); err != nil {
+			return nil, err
+		}
+		base = fmt.Sprintf("d %d", interval)
+	case "HOURLY":
+		if err := onlyParams(params, "FREQ", "INTERVAL"); err != nil {
+			return nil, err
+		}
+		base = fmt.Sprintf("h %d", interval)
+	case "MINUTELY":
+		if err := onlyParams(params, "FREQ", "INTERVAL"); err != nil {
+			return nil, err
+		}
+		base = fmt.Sprintf("min %d", interval)
+	case "WEEKLY":
+		if err := onlyParams(params, "FREQ", "INTERVAL", "BYDAY", "WKST"); err != nil {
+			return nil, err
+		}
+		byDay, ok := params["BYDAY"]
+		if !ok {
+			// Еженедельное повторение без дней недели — то же, что повторение через 7 дней.
+			base = fmt.Sprintf("d %d", 7*interval)
+			break
+		}
+		if interval != 1 {
+			return nil, rruleError("INTERVAL", CodeUnsupported, "WEEKLY с INTERVAL больше 1 и BYDAY не поддерживается")
+		}
+		days, err := parseRRULEDays(byDay)
+		if err != nil {
+			return nil, err
+		}
+		base = "w " + days
+	case "MONTHLY":
+		if err := onlyParams(params, "FREQ", "INTERVAL", "BYMONTHDAY", "BYDAY", "BYMONTH"); err != nil {
+			return nil, err
+		}
+		if interval != 1 {
+			return nil, rruleError("INTERVAL", CodeUnsupported, "MONTHLY с INTERVAL больше 1 не поддерживается")
+		}
+		byMonthDay, hasMonthDay := params["BYMONTHDAY"]
+		byDay, hasDay := params["BYDAY"]
+		switch {
+		case hasMonthDay && hasDay:
+			return nil, rruleError("BYDAY", CodeUnsupported, "MONTHLY с BYMONTHDAY и BYDAY одновременно не поддерживается")
+		case hasMonthDay:
+			base = "m " + byMonthDay
+		case hasDay:
+			ordinals, days, err := parseRRULEOrdinalDays(byDay)
+			if err != nil {
+				return nil, err
+			}
+			base = "wm " + ordinals + " " + days
+		default:
+			return nil, rruleError("FREQ", CodeUnsupported, "MONTHLY без BYMONTHDAY или BYDAY не поддерживается")
+		}
+		if byMonth, ok := params["BYMONTH"]; ok {
+			base += " " + byMonth
+		}
+	case "YEARLY":
+		if err := onlyParams(params, "FREQ", "INTERVAL", "BYMONTH", "BYMONTHDAY"); err != nil {
+			return nil, err
+		}
+		if interval != 1 {
+			return nil, rruleError("INTERVAL", CodeUnsupported, "YEARLY с INTERVAL больше 1 не поддерживается")
+		}
+		byMonth, hasMonth := params["BYMONTH"]
+		byMonthDay, hasMonthDay := params["BYMONTHDAY"]
+		if hasMonth != hasMonthDay {
+			return nil, rruleError("FREQ", CodeUnsupported, "YEARLY поддерживается либо без BYMONTH и BYMONTHDAY, либо с обоими")
+		}
+		base = "y"
+		if hasMonth {
+			dates, err := parseRRULEYearDates(byMonth, byMonthDay)
+			if err != nil {
+				return nil, err
+			}
+			base += " " + dates
+		}
+	case "":
+		return nil, rruleError("", CodeMissingValue, "не указан параметр FREQ")
+	default:
+		return nil, rruleError("FREQ", CodeUnsupported, "FREQ=%s не поддерживается", freq)
+	}
+
+	if until, ok := params["UNTIL"]; ok {
+		// Время в UNTIL отбрасывается: правила повторения работают с датами.
+		date, err := time.Parse("20060102", until[:min(len(until), 8)])
+		if err != nil {
+			return nil, rruleError("UNTIL", CodeInvalidDate, "UNTIL должен начинаться с даты в формате YYYYMMDD")
+		}
+		base += " until " + date.Format("20060102")
+	}
+	if count, ok := params["COUNT"]; ok {
+		base += " count " + count
+	}
+	rule, err := Parse(base)
+	if err != nil {
+		return nil, rruleError("", errorCode(err), "%s", err)
+	}
+	return rule, nil
+}
+
+// onlyParams проверяет, что в params нет параметров, кроме allowed, UNTIL и COUNT.
+func onlyParams(params map[string]string, allowed ...string) error {
+	for key := range params {
+		if key == "UNTIL" || key == "COUNT" || slices.Contains(allowed, key) {
+			continue
+		}
+		return rruleError(key, CodeUnsupported, "параметр %s не поддерживается для FREQ=%s", key, params["FREQ"])
+	}
+	if value, ok := params["WKST"]; ok && value != "MO" {
+		return rruleError("WKST", CodeUnsupported, "поддерживается только WKST=MO")
+	}
+	return nil
+}
+
+// parseRRULEDays преобразует значение BYDAY (например, "MO,WE") в список дней правила w.
+func parseRRULEDays(value string) (string, error) {
+	var days []int
+	for _, item := range strings.Split(value, ",") {
+		day := slices.Index(rruleDays, item) + 1
+		if day == 0 {
+			return "", rruleError("BYDAY", CodeInvalidValue, "некорректный день недели %q в BYDAY", item)
+		}
+		days = append(days, day)
+	}
+	return joinList(days), nil
+}
+
+// parseRRULEOrdinalDays преобразует значение BYDAY с порядковыми номерами (например, "2TU,-1FR")
+// в списки номеров и дней недели правила wm. Набор должен быть полным произведением номеров
+// на дни недели, иначе его нельзя выразить одним правилом wm.
+func parseRRULEOrdinalDays(value string) (string, string, error) {
+	pairs := map[[2]int]bool{}
+	var ordinals, days []int
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 3 {
+			return "", "", rruleError("BYDAY", CodeInvalidValue, "некорректный день недели %q в BYDAY", item)
+		}
+		day := slices.Index(rruleDays, item[len(item)-2:]) + 1
+		if day == 0 {
+			return "", "", rruleError("BYDAY", CodeInvalidValue, "некорректный день недели %q в BYDAY", item)
+		}
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(item[:len(item)-2], "+"))
+		if err != nil {
+			return "", "", rruleError("BYDAY", CodeUnsupported, "BYDAY %q без порядкового номера не поддерживается для MONTHLY", item)
+		}
+		pairs[[2]int{ordinal, day}] = true
+		if !slices.Contains(ordinals, ordinal) {
+			ordinals = append(ordinals, ordinal)
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	if len(pairs) != len(ordinals)*len(days) {
+		return "", "", rruleError("BYDAY", CodeUnsupported, "BYDAY=%s нельзя выразить одним правилом wm", value)
+	}
+	return joinList(ordinals), joinList(days), nil
+}
+
+// parseRRULEYearDates преобразует значения BYMONTH и BYMONTHDAY в список дат MMDD правила y.
+func parseRRULEYearDates(byMonth, byMonthDay string) (string, error) {
+	months, err := parseList(byMonth, 1, 12)
+	if err != nil {
+		return "", rruleError("BYMONTH", errorCode(err), "некорректный BYMONTH: %s", err)
+	}
+	days, err := parseList(byMonthDay, 1, 31)
+	if err != nil {
+		return "", rruleError("BYMONTHDAY", errorCode(err), "BYMONTHDAY для YEARLY поддерживается только с положительными днями: %s", err)
+	}
+	var dates []string
+	for _, month := range months {
+		for _, day := range days {
+			if month == 2 && day == 29 {
+				return "", rruleError("BYMONTHDAY", CodeUnsupported, "29 февраля для YEARLY не поддерживается")
+			}
+			dates = append(dates, fmt.Sprintf("%02d%02d", month, day))
+		}
+	}
+	return strings.Join(dates, ","), nil
+}
@@ -0,0 +1,292 @@
+package repeat
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Daily — правило "d <число>": повторение через заданное число дней.
+type Daily struct {
+	Interval int
+}
+
+func parseDaily(args []string) (Rule, error) {
+	if err := checkArgs("d", args, 1, 1, "не указан интервал в днях"); err != nil {
+		return nil, err
+	}
+	interval, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, argError(0, CodeNotNumber, "d: интервал в днях должен быть числом")
+	}
+	if interval <= 0 || interval > 400 {
+		return nil, argError(0, CodeOutOfRange, "d %d: превышен максимально допустимый интервал (1-400)", interval)
+	}
+	return Daily{Interval: interval}, nil
+}
+
+func (r Daily) Next(after time.Time) time.Time {
+	return after.AddDate(0, 0, r.Interval)
+}
+
+func (r Daily) String() string {
+	return fmt.Sprintf("d %d", r.Interval)
+}
+
+// Yearly — правило "y [<даты>] [feb28|mar1]": ежегодное повторение.
+// Без списка дат задача повторяется каждый год в дату задачи; при этом 29 февраля
+// в невисокосный год становится 1 марта. Со списком дат в формате MMDD ("y 0315,1225")
+// задача повторяется в каждую из них, а для 29 февраля в невисокосный год можно выбрать
+// 28 февраля (feb28) или 1 марта (mar1, по умолчанию).
+type Yearly struct {
+	// Dates — даты в виде числа MMDD, например 315 для 15 марта.
+	Dates []int
+	// Feb28 означает, что 29 февраля в невисокосный год переносится на 28 февраля, а не на 1 марта.
+	Feb28 bool
+}
+
+func parseYearly(args []string) (Rule, error) {
+	if len(args) == 0 {
+		return Yearly{}, nil
+	}
+	if args[0] == "feb28" || args[0] == "mar1" {
+		return nil, argError(0, CodeInvalidValue, "y: правило для 29 февраля указывается только после списка дат MMDD")
+	}
+	if err := checkArgs("y", args, 1, 2, ""); err != nil {
+		return nil, err
+	}
+
+	var rule Yearly
+	offset := 0
+	for _, item := range strings.Split(args[0], ",") {
+		// Без года подставляется нулевой год, он високосный, поэтому 0229 считается допустимой датой.
+		date, err := time.Parse("0102", item)
+		if err != nil || len(item) != 4 {
+			return nil, &ParseError{Code: CodeInvalidDate, Token: item, Pos: offset,
+				Message: fmt.Sprintf("y: некорректная дата %q, ожидается формат MMDD", item)}
+		}
+		offset += len(item) + 1
+		mmdd := int(date.Month())*100 + date.Day()
+		if !slices.Contains(rule.Dates, mmdd) {
+			rule.Dates = append(rule.Dates, mmdd)
+		}
+	}
+	slices.Sort(rule.Dates)
+
+	if len(args) == 2 {
+		switch args[1] {
+		case "feb28":
+			rule.Feb28 = true
+		case "mar1":
+		default:
+			return nil, argError(1, CodeInvalidValue, "y: неизвестное правило для 29 февраля %q, ожидается feb28 или mar1", args[1])
+		}
+	}
+	return rule, nil
+}
+
+func (r Yearly) Next(after time.Time) time.Time {
+	if len(r.Dates) == 0 {
+		return after.AddDate(1, 0, 0)
+	}
+	return searchDay(after, func(date time.Time) bool {
+		mmdd := int(date.Month())*100 + date.Day()
+		if slices.Contains(r.Dates, mmdd) {
+			return true
+		}
+		// В невисокосный год 29 февраля заменяется на 28 февраля или 1 марта.
+		if daysInMonth(time.Date(date.Year(), time.February, 1, 0, 0, 0, 0, time.UTC)) == 29 || !slices.Contains(r.Dates, 229) {
+			return false
+		}
+		return (r.Feb28 && mmdd == 228) || (!r.Feb28 && mmdd == 301)
+	})
+}
+
+func (r Yearly) String() string {
+	if len(r.Dates) == 0 {
+		return "y"
+	}
+	dates := make([]string, len(r.Dates))
+	for i, mmdd := range r.Dates {
+		dates[i] = fmt.Sprintf("%04d", mmdd)
+	}
+	s := "y " + strings.Join(dates, ",")
+	if r.Feb28 {
+		s += " feb28"
+	}
+	return s
+}
+
+// Monthly — правило "m <дни> [<месяцы>]": повторение в указанные дни месяца.
+// Дни -1 и -2 означают последний и предпоследний день месяца.
+// Пустой список месяцев означает любой месяц.
+type Monthly struct {
+	Days   []int
+	Months []int
+}
+
+func parseMonthly(args []string) (Rule, error) {
+	if err := checkArgs("m", args, 1, 2, "не указаны дни месяца"); err != nil {
+		return nil, err
+	}
+	days, err := parseList(args[0], -2, 31)
+	if err != nil {
+		return nil, listError(0, err, "m: некорректный список дней месяца")
+	}
+	var months []int
+	if len(args) == 2 {
+		months, err = parseList(args[1], 1, 12)
+		if err != nil {
+			return nil, listError(1, err, "m: некорректный список месяцев")
+		}
+	}
+	if !monthDaysPossible(days, months) {
+		return nil, argError(0, CodeNeverOccurs, "m: ни один из указанных дней не встречается в указанных месяцах")
+	}
+	sortList(days)
+	sortList(months)
+	return Monthly{Days: days, Months: months}, nil
+}
+
+// monthDaysPossible проверяет, что хотя бы один из дней days существует хотя бы в одном из месяцев months.
+func monthDaysPossible(days, months []int) bool {
+	if len(months) == 0 {
+		months = []int{1}
+	}
+	for _, day := range days {
+		for _, month := range months {
+			// Високосный 2024 год, чтобы 29 февраля считалось допустимым днём.
+			if day < 0 || day <= daysInMonth(time.Date(2024, time.Month(month), 1, 0, 0, 0, 0, time.UTC)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r Monthly) Next(after time.Time) time.Time {
+	return searchDay(after, func(date time.Time) bool {
+		if len(r.Months) > 0 && !slices.Contains(r.Months, int(date.Month())) {
+			return false
+		}
+		day := date.Day()
+		return slices.Contains(r.Days, day) || slices.Contains(r.Days, day-daysInMonth(date)-1)
+	})
+}
+
+func (r Monthly) String() string {
+	if len(r.Months) == 0 {
+		return "m " + joinList(r.Days)
+	}
+	return "m " + joinList(r.Days) + " " + joinList(r.Months)
+}
+
+// Weekly — правило "w <дни недели>": повторение в указанные дни недели (1 — понедельник, 7 — воскресенье).
+type Weekly struct {
+	Days []int
+}
+
+func parseWeekly(args []string) (Rule, error) {
+	if err := checkArgs("w", args, 1, 1, "не указаны дни недели"); err != nil {
+		return nil, err
+	}
+	days, err := parseList(args[0], 1, 7)
+	if err != nil {
+		return nil, listError(0, err, "w: некорректный список дней недели")
+	}
+	sortList(days)
+	return Weekly{Days: days}, nil
+}
+
+func (r Weekly) Next(after time.Time) time.Time {
+	return searchDay(after, func(date time.Time) bool {
+		return slices.Contains(r.Days, isoWeekday(date))
+	})
+}
+
+func (r Weekly) String() string {
+	return "w " + joinList(r.Days)
+}
+
+// MonthlyWeekday — правило "wm <порядковые номера> <дни недели> [<месяцы>]": повторение
+// в указанный по счёту день недели месяца, например "wm 2 2" — каждый второй вторник.
+// Отрицательные номера отсчитываются от конца месяца: "wm -1 5" — последняя пятница.
+// Пустой список месяцев означает любой месяц.
+type MonthlyWeekday struct {
+	Ordinals []int
+	Weekdays []int
+	Months   []int
+}
+
+func parseMonthlyWeekday(args []string) (Rule, error) {
+	if err := checkArgs("wm", args, 2, 3, "не указаны порядковые номера и дни недели"); err != nil {
+		return nil, err
+	}
+	ordinals, err := parseList(args[0], -5, 5)
+	if err != nil {
+		return nil, listError(0, err, "wm: некорректный список порядковых номеров")
+	}
+	weekdays, err := parseList(args[1], 1, 7)
+	if err != nil {
+		return nil, listError(1, err, "wm: некорректный список дней недели")
+	}
+	var months []int
+	if len(args) == 3 {
+		months, err = parseList(args[2], 1, 12)
+		if err != nil {
+			return nil, listError(2, err, "wm: некорректный список месяцев")
+		}
+	}
+	if !monthWeekdaysPossible(ordinals, months) {
+		return nil, argError(0, CodeNeverOccurs, "wm: пятый день недели в феврале бывает только раз в 28 лет, укажите другой порядковый номер или месяц")
+	}
+	sortList(ordinals)
+	sortList(weekdays)
+	sortList(months)
+	return MonthlyWeekday{Ordinals: ordinals, Weekdays: weekdays, Months: months}, nil
+}
+
+// monthWeekdaysPossible проверяет, что правило wm с порядковыми номерами ordinals в месяцах months
+// даёт даты достаточно часто, чтобы найти их за maxSearchDays. Первые четыре и последние четыре
+// дня недели есть в каждом месяце, а пятые — только в месяцах длиннее 28 дней: в феврале
+// пятый день недели выпадает лишь в високосный год, раз в 28 лет для каждого дня недели.
+func monthWeekdaysPossible(ordinals, months []int) bool {
+	for _, ordinal := range ordinals {
+		if ordinal >= -4 && ordinal <= 4 {
+			return true
+		}
+	}
+	if len(months) == 0 {
+		return true
+	}
+	for _, month := range months {
+		if month != int(time.February) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r MonthlyWeekday) Next(after time.Time) time.Time {
+	return searchDay(after, func(date time.Time) bool {
+		if len(r.Months) > 0 && !slices.Contains(r.Months, int(date.Month())) {
+			return false
+		}
+		if !slices.Contains(r.Weekdays, isoWeekday(date)) {
+			return false
+		}
+		fromStart := (date.Day()-1)/7 + 1
+		fromEnd := -((daysInMonth(date)-date.Day())/7 + 1)
+		return slices.Contains(r.Ordinals, fromStart) || slices.Contains(r.Ordinals, fromEnd)
+	})
+}
+
+func (r MonthlyWeekday) String() string {
+	s := "wm " + joinList(r.Ordinals) + " " + joinList(r.Weekdays)
+	if len(r.Months) > 0 {
+		s += " " + joinList(r.Months)
+	}
+	return s
+}
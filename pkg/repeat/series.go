@@ -0,0 +1,118 @@
+package repeat
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Series — правило повторения с модификаторами серии: условием окончания и точкой отсчёта.
+// Записывается как базовое правило с модификаторами: "d 7 until 20250101", "w 1,3 count 5", "d 5 after".
+type Series struct {
+	Rule Rule
+	// Until — последняя дата, на которую может приходиться повторение. Нулевое значение — без ограничения.
+	Until time.Time
+	// Count — сколько повторений осталось, включая текущее. 0 — без ограничения.
+	Count int
+	// AfterDone означает, что следующая дата отсчитывается от фактической даты выполнения,
+	// а не от даты по расписанию.
+	AfterDone bool
+}
+
+// seriesHint — подсказка по записи модификаторов серии.
+const seriesHint = "после правила можно указать модификаторы until YYYYMMDD, count <число> и after, например d 7 until 20251231"
+
+// isModifier возвращает true, если token — ключевое слово модификатора правила.
+func isModifier(token string) bool {
+	return token == "until" || token == "count" || token == "after"
+}
+
+// parseSeries применяет к правилу rule модификаторы серии.
+func parseSeries(rule Rule, args []string) (Rule, error) {
+	series := Series{Rule: rule}
+	for i := 0; i < len(args); {
+		name := args[i]
+		// Модификатор after не имеет значения.
+		if name == "after" {
+			if series.AfterDone {
+				return nil, argError(i, CodeDuplicate, "after: модификатор указан несколько раз")
+			}
+			series.AfterDone = true
+			i++
+			continue
+		}
+		if !isModifier(name) {
+			return nil, argError(i, CodeUnknownModifier, "неизвестный модификатор правила: %s", name)
+		}
+		if i+1 >= len(args) || isModifier(args[i+1]) {
+			return nil, argError(i+1, CodeMissingValue, "%s: не указано значение", name)
+		}
+		value := args[i+1]
+		switch name {
+		case "until":
+			if !series.Until.IsZero() {
+				return nil, argError(i, CodeDuplicate, "until: модификатор указан несколько раз")
+			}
+			until, err := time.Parse("20060102", value)
+			if err != nil {
+				return nil, argError(i+1, CodeInvalidDate, "until: дата окончания должна быть в формате 20060102")
+			}
+			series.Until = until
+		case "count":
+			if series.Count != 0 {
+				return nil, argError(i, CodeDuplicate, "count: модификатор указан несколько раз")
+			}
+			count, err := strconv.Atoi(value)
+			if err != nil || count <= 0 {
+				return nil, argError(i+1, CodeOutOfRange, "count: число повторений должно быть положительным числом")
+			}
+			series.Count = count
+		}
+		i += 2
+	}
+	return series, nil
+}
+
+func (r Series) Next(after time.Time) time.Time {
+	next := r.Rule.Next(after)
+	// Until — дата без времени, поэтому повторения в течение этого дня ещё допустимы.
+	if next.IsZero() || (!r.Until.IsZero() && !next.Before(r.Until.AddDate(0, 0, 1))) {
+		return time.Time{}
+	}
+	return next
+}
+
+func (r Series) String() string {
+	var sb strings.Builder
+	sb.WriteString(r.Rule.String())
+	if !r.Until.IsZero() {
+		sb.WriteString(" until " + r.Until.Format("20060102"))
+	}
+	if r.Count > 0 {
+		sb.WriteString(" count " + strconv.Itoa(r.Count))
+	}
+	if r.AfterDone {
+		sb.WriteString(" after")
+	}
+	return sb.String()
+}
+
+// Complete возвращает правило, которое действует после выполнения очередного повторения,
+// и false, если выполненное повторение было последним в серии.
+func Complete(rule Rule) (Rule, bool) {
+	series, ok := rule.(Series)
+	if !ok || series.Count == 0 {
+		return rule, true
+	}
+	if series.Count == 1 {
+		return nil, false
+	}
+	series.Count--
+	return series, true
+}
+
+// AfterDone возвращает true, если следующая дата по правилу отсчитывается от даты выполнения.
+func AfterDone(rule Rule) bool {
+	series, ok := rule.(Series)
+	return ok && series.AfterDone
+}
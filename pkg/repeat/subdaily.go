@@ -0,0 +1,61 @@
+package repeat
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SubDaily — правила "h <часы>" и "min <минуты>": повторение несколько раз в день
+// через фиксированный промежуток времени. Такие правила требуют, чтобы у задачи было указано время.
+type SubDaily struct {
+	Step time.Duration
+}
+
+func parseHourly(args []string) (Rule, error) {
+	if err := checkArgs("h", args, 1, 1, "не указан интервал в часах"); err != nil {
+		return nil, err
+	}
+	hours, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, argError(0, CodeNotNumber, "h: интервал в часах должен быть числом")
+	}
+	if hours <= 0 || hours > 24 {
+		return nil, argError(0, CodeOutOfRange, "h %d: превышен максимально допустимый интервал (1-24)", hours)
+	}
+	return SubDaily{Step: time.Duration(hours) * time.Hour}, nil
+}
+
+func parseMinutely(args []string) (Rule, error) {
+	if err := checkArgs("min", args, 1, 1, "не указан интервал в минутах"); err != nil {
+		return nil, err
+	}
+	minutes, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, argError(0, CodeNotNumber, "min: интервал в минутах должен быть числом")
+	}
+	if minutes <= 0 || minutes > 1440 {
+		return nil, argError(0, CodeOutOfRange, "min %d: превышен максимально допустимый интервал (1-1440)", minutes)
+	}
+	return SubDaily{Step: time.Duration(minutes) * time.Minute}, nil
+}
+
+func (r SubDaily) Next(after time.Time) time.Time {
+	return after.Add(r.Step)
+}
+
+func (r SubDaily) String() string {
+	if r.Step%time.Hour == 0 {
+		return fmt.Sprintf("h %d", r.Step/time.Hour)
+	}
+	return fmt.Sprintf("min %d", r.Step/time.Minute)
+}
+
+// Step возвращает шаг повторения, если rule — правило h или min (в том числе с модификаторами серии).
+func Step(rule Rule) (time.Duration, bool) {
+	if series, ok := rule.(Series); ok {
+		rule = series.Rule
+	}
+	subDaily, ok := rule.(SubDaily)
+	return subDaily.Step, ok
+}
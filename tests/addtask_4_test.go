@@ -88,7 +88,7 @@ func TestAddTask(t *testing.T) {
 	tbl := []task{
 		{"20240129", "", "", ""},
 		{"20240192", "Qwerty", "", ""},
-		{"28.01.2024", "Заголовок", "", ""},
+		{"28.13.2024", "Заголовок", "", ""},
 		{"20240112", "Заголовок", "", "w"},
 		{"20240212", "Заголовок", "", "ooops"},
 	}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateFormats(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	date := time.Now().AddDate(0, 0, 3)
+	for _, layout := range []string{`20060102`, `2006-01-02`, `02.01.2006`} {
+		m, err := postJSON("api/task", map[string]any{
+			"date":  date.Format(layout),
+			"title": "Дата в формате " + layout,
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, m["id"], "Не возвращён id для формата %s: %v", layout, m["error"]) {
+			continue
+		}
+		id := fmt.Sprint(m["id"])
+
+		var stored Task
+		err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+		assert.NoError(t, err)
+		assert.Equal(t, date.Format(`20060102`), stored.Date, layout)
+
+		body, err := getBody("api/task?id=" + id + "&date_format=iso")
+		assert.NoError(t, err)
+		var task map[string]string
+		assert.NoError(t, json.Unmarshal(body, &task))
+		assert.Equal(t, date.Format(`2006-01-02`), task["date"])
+	}
+
+	tbl := []struct {
+		query string
+		want  string
+	}{
+		{"now=2024-01-26&date=2024-01-20&repeat=d+7", "20240127"},
+		{"now=26.01.2024&date=20.01.2024&repeat=d+7", "20240127"},
+		{"now=20240126&date=20240120&repeat=d+7&date_format=iso", "2024-01-27"},
+		{"now=20240126&date=20240120&repeat=d+7&date_format=ru", "27.01.2024"},
+		{"now=2024-01-26+10:00&date=2024-01-26&time=09:00&repeat=h+2&date_format=ru", "26.01.2024 11:00"},
+	}
+	for _, v := range tbl {
+		body, err := getBody("api/nextdate?" + v.query)
+		assert.NoError(t, err)
+		assert.Equal(t, v.want, string(body), v.query)
+	}
+
+	body, err := getBody("api/occurrences?now=20240126&date=20240120&repeat=d+7&count=2&date_format=iso")
+	assert.NoError(t, err)
+	var dates []string
+	assert.NoError(t, json.Unmarshal(body, &dates))
+	assert.Equal(t, []string{"2024-01-27", "2024-02-03"}, dates)
+
+	req, err := http.NewRequest(http.MethodGet, getURL("api/nextdate?now=20240126&date=20240120&repeat=d+7"), nil)
+	assert.NoError(t, err)
+	req.Header.Set("X-Date-Format", "02.01.2006")
+	resp, err := http.DefaultClient.Do(req)
+	if assert.NoError(t, err) {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, "27.01.2024", string(body))
+	}
+
+	resp, err = http.Get(getURL("api/tasks?date_format=unknown"))
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateInput(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	day := func(n int) string {
+		return now.AddDate(0, 0, n).Format(`20060102`)
+	}
+	nextWeekday := func(wd time.Weekday) string {
+		return day((int(wd)-int(now.Weekday())+6)%7 + 1)
+	}
+
+	tbl := []struct {
+		input string
+		want  string
+	}{
+		{"today", day(0)},
+		{"Tomorrow", day(1)},
+		{"day after tomorrow", day(2)},
+		{"+3d", day(3)},
+		{"+2w", day(14)},
+		{"in 5 days", day(5)},
+		{"next monday", nextWeekday(time.Monday)},
+		{"friday", nextWeekday(time.Friday)},
+		{"сегодня", day(0)},
+		{"завтра", day(1)},
+		{"послезавтра", day(2)},
+		{"через 3 дня", day(3)},
+		{"через 10 дней", day(10)},
+		{"через неделю", day(7)},
+		{"в следующий понедельник", nextWeekday(time.Monday)},
+		{"в среду", nextWeekday(time.Wednesday)},
+	}
+	for _, v := range tbl {
+		m, err := postJSON("api/task", map[string]any{
+			"date":  v.input,
+			"title": "Относительная дата",
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, m["id"], "Не возвращён id для даты %q: %v", v.input, m["error"]) {
+			continue
+		}
+
+		var stored Task
+		err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, fmt.Sprint(m["id"]))
+		assert.NoError(t, err)
+		assert.Equal(t, v.want, stored.Date, v.input)
+	}
+
+	for _, input := range []string{"someday", "+0d", "через много дней", "next moonday"} {
+		m, err := postJSON("api/task", map[string]any{
+			"date":  input,
+			"title": "Неразборчивая дата",
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.Contains(t, fmt.Sprint(m["error"]), "завтра", input)
+	}
+}
@@ -11,11 +11,15 @@ import (
 )
 
 type Task struct {
-	ID      int64  `db:"id"`
-	Date    string `db:"date"`
-	Title   string `db:"title"`
-	Comment string `db:"comment"`
-	Repeat  string `db:"repeat"`
+	ID        int64  `db:"id"`
+	Date      string `db:"date"`
+	Title     string `db:"title"`
+	Comment   string `db:"comment"`
+	Repeat    string `db:"repeat"`
+	Time      string `db:"time"`
+	Priority  int    `db:"priority"`
+	ListID    int64  `db:"list_id"`
+	DeletedAt string `db:"deleted_at"`
 }
 
 func count(db *sqlx.DB) (int, error) {
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func blockedTasks(t *testing.T) map[string]bool {
+	body, err := requestJSON("api/tasks", nil, http.MethodGet)
+	assert.NoError(t, err)
+	var resp struct {
+		Tasks []struct {
+			ID      string `json:"id"`
+			Blocked bool   `json:"blocked"`
+		} `json:"tasks"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	blocked := map[string]bool{}
+	for _, task := range resp.Tasks {
+		blocked[task.ID] = task.Blocked
+	}
+	return blocked
+}
+
+func TestDependencies(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+
+	date := time.Now().Format(`20060102`)
+	a := addTask(t, task{date: date, title: "Собрать сборку"})
+	b := addTask(t, task{date: date, title: "Выкатить релиз"})
+	c := addTask(t, task{date: date, title: "Написать анонс", repeat: "d 1"})
+
+	// b ждёт a, a ждёт c.
+	for _, link := range [][2]string{{b, a}, {a, c}} {
+		ret, err := postJSON("api/task/dependencies?id="+link[0]+"&blocker="+link[1], nil, http.MethodPost)
+		assert.NoError(t, err)
+		assert.Nil(t, ret["error"])
+	}
+	assert.Equal(t, map[string]bool{a: true, b: true, c: false}, blockedTasks(t))
+
+	// Зависимости, замыкающие цикл, отклоняются.
+	for _, link := range [][2]string{{c, b}, {a, b}, {a, a}} {
+		ret, err := postJSON("api/task/dependencies?id="+link[0]+"&blocker="+link[1], nil, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ret["error"], "%s blocked by %s", link[0], link[1])
+	}
+
+	body, err := requestJSON("api/task/dependencies?id="+a, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var deps struct {
+		BlockedBy []string `json:"blocked_by"`
+		Blocks    []string `json:"blocks"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &deps))
+	assert.Equal(t, []string{c}, deps.BlockedBy)
+	assert.Equal(t, []string{b}, deps.Blocks)
+
+	// Выполнение повторяющейся задачи c снимает блокировку с a.
+	ret, err := postJSON("api/task/done?id="+c, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	assert.Equal(t, map[string]bool{a: false, b: true, c: false}, blockedTasks(t))
+
+	// Выполнение одноразовой задачи a перемещает её в корзину и снимает блокировку с b.
+	ret, err = postJSON("api/task/done?id="+a, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	assert.Equal(t, map[string]bool{b: false, c: false}, blockedTasks(t))
+	body, err = requestJSON("api/task/dependencies?id="+b, nil, http.MethodGet)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(body, &deps))
+	assert.Empty(t, deps.BlockedBy)
+
+	// Окончательное удаление задачи удаляет и её зависимости.
+	ret, err = postJSON("api/trash/purge?id="+a, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	var links int
+	assert.NoError(t, db.Get(&links, `SELECT count(*) FROM dependencies`))
+	assert.Equal(t, 0, links)
+
+	_, err = db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTaskLang(t *testing.T, id, lang string) map[string]string {
+	req, err := http.NewRequest(http.MethodGet, getURL("api/task?id="+id), nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Language", lang)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	return m
+}
+
+func TestRepeatText(t *testing.T) {
+	tbl := []struct {
+		repeat string
+		time   string
+		ru     string
+		en     string
+	}{
+		{"d 1", "", "каждый день", "every day"},
+		{"d 30", "", "каждые 30 дней", "every 30 days"},
+		{"d 3", "", "каждые 3 дня", "every 3 days"},
+		{"d 21", "", "каждый 21 день", "every 21 days"},
+		{"y", "", "каждый год", "every year"},
+		{"y 0315,1225", "", "каждый год 15 марта и 25 декабря", "every year on March 15 and December 25"},
+		{"y 0229 feb28", "", "каждый год 29 февраля (в невисокосный год — 28 февраля)",
+			"every year on February 29 (February 28 in non-leap years)"},
+		{"m -1,18", "", "каждый месяц: 18 и последнее число", "every month on day 18 and last day"},
+		{"m 1 1,2", "", "каждый месяц: 1 (месяцы: январь, февраль)", "every month on day 1 in January and February"},
+		{"w 1,3,5", "", "по понедельникам, средам и пятницам", "every Monday, Wednesday and Friday"},
+		{"wm 2 3", "", "каждый месяц: вторая среда", "every month on the second Wednesday"},
+		{"wm -1 5", "", "каждый месяц: последняя пятница", "every month on the last Friday"},
+		{"b 1", "", "каждый рабочий день", "every working day"},
+		{"b 3", "", "каждые 3 рабочих дня", "every 3 working days"},
+		{"bm 1", "", "каждый месяц: первый рабочий день", "every month on the first working day"},
+		{"h 4", "08:00", "каждые 4 часа", "every 4 hours"},
+		{"min 90", "08:00", "каждые 90 минут", "every 90 minutes"},
+		{"d 7 count 3 after", "", "каждые 7 дней, осталось повторений: 3, считая от даты выполнения",
+			"every 7 days, 3 occurrences left, counting from completion"},
+		{"d 7 until 20990101", "", "каждые 7 дней, по 01.01.2099", "every 7 days, until 2099-01-01"},
+	}
+	today := time.Now().Format(`20060102`)
+	for _, v := range tbl {
+		id := addTimedTask(t, today, v.time, "Описание правила", v.repeat)
+
+		m := getTaskLang(t, id, "ru-RU,ru;q=0.9")
+		assert.Equal(t, v.ru, m["repeat_text"], v.repeat)
+		m = getTaskLang(t, id, "en-US,en;q=0.9,ru;q=0.8")
+		assert.Equal(t, v.en, m["repeat_text"], v.repeat)
+	}
+
+	id := addTask(t, task{date: today, title: "Без повторения"})
+	m := getTaskLang(t, id, "en")
+	_, ok := m["repeat_text"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExceptions(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	day := func(n int) string {
+		return now.AddDate(0, 0, n).Format(`20060102`)
+	}
+	id := addTask(t, task{
+		date:   day(0),
+		title:  "Бассейн",
+		repeat: "d 7",
+	})
+
+	ret, err := postJSON("api/task/exceptions?id="+id+"&date="+day(7), nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	body, err := getBody("api/occurrences?id=" + id + "&date=" + day(0) + "&repeat=d+7&count=2")
+	assert.NoError(t, err)
+	var dates []string
+	assert.NoError(t, json.Unmarshal(body, &dates))
+	assert.Equal(t, []string{day(14), day(21)}, dates)
+
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	var stored Task
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, day(14), stored.Date)
+
+	ret, err = postJSON("api/task/skip?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, day(21), stored.Date)
+
+	body, err = requestJSON("api/task/exceptions?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var m map[string][]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.Equal(t, []string{day(7), day(14)}, m["dates"])
+
+	ret, err = postJSON("api/task/exceptions?id="+id+"&date="+day(7), nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	ret, err = postJSON("api/task/exceptions?id="+id+"&date=2024-13-01", nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// Даты-исключения принимаются и возвращаются в тех же форматах, что и даты задач.
+	iso := now.AddDate(0, 0, 28).Format(`2006-01-02`)
+	ret, err = postJSON("api/task/exceptions?id="+id+"&date="+iso, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	body, err = requestJSON("api/task/exceptions?id="+id+"&date_format=ru", nil, http.MethodGet)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(body, &m))
+	ru := now.AddDate(0, 0, 28).Format(`02.01.2006`)
+	assert.Equal(t, []string{now.AddDate(0, 0, 14).Format(`02.01.2006`), ru}, m["dates"])
+	ret, err = postJSON("api/task/exceptions?id="+id+"&date="+ru, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	ret, err = postJSON("api/task?id="+id, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	ret, err = postJSON("api/trash/purge?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	var left int
+	err = db.Get(&left, `SELECT count(*) FROM exceptions WHERE task_id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, left)
+
+	id = addTask(t, task{
+		date:  day(0),
+		title: "Разовая задача",
+	})
+	ret, err = postJSON("api/task/skip?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type completion struct {
+	TaskID string `json:"task_id"`
+	Date   string `json:"date"`
+	Title  string `json:"title"`
+	DoneAt string `json:"done_at"`
+}
+
+func getHistory(t *testing.T, query string) []completion {
+	body, err := requestJSON("api/history"+query, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var resp struct {
+		Completions []completion `json:"completions"`
+		Error       string       `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	assert.Empty(t, resp.Error)
+	return resp.Completions
+}
+
+func TestHistory(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	for _, table := range []string{"scheduler", "completions"} {
+		_, err := db.Exec("DELETE FROM " + table)
+		assert.NoError(t, err)
+	}
+
+	now := time.Now()
+	today := now.Format(`20060102`)
+	once := addTask(t, task{date: today, title: "Позвонить врачу"})
+	daily := addTask(t, task{date: today, title: "Зарядка", repeat: "d 1"})
+
+	ret, err := postJSON("api/task/done?id="+once, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	ret, err = postJSON("api/task/done?id="+daily, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	// Переименование задачи не меняет уже записанную историю.
+	ret, err = postJSON("api/task", map[string]any{
+		"id":     daily,
+		"date":   now.AddDate(0, 0, 1).Format(`20060102`),
+		"title":  "Утренняя зарядка",
+		"repeat": "d 1",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	ret, err = postJSON("api/task/done?id="+daily, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	history := getHistory(t, "")
+	assert.Equal(t, 3, len(history))
+	titles := []string{}
+	for _, c := range history {
+		titles = append(titles, c.Title)
+		_, err := time.Parse(time.RFC3339, c.DoneAt)
+		assert.NoError(t, err)
+	}
+	assert.ElementsMatch(t, []string{"Позвонить врачу", "Зарядка", "Утренняя зарядка"}, titles)
+
+	// В истории сохраняется дата выполненного повторения, а не следующая.
+	repeats := getHistory(t, "?task_id="+daily)
+	assert.Equal(t, 2, len(repeats))
+	dates := []string{}
+	for _, c := range repeats {
+		dates = append(dates, c.Date)
+	}
+	assert.ElementsMatch(t, []string{today, now.AddDate(0, 0, 1).Format(`20060102`)}, dates)
+
+	// Фильтр по датам выполнения, границы включаются.
+	assert.Equal(t, 3, len(getHistory(t, "?from="+today+"&to="+today)))
+	assert.Equal(t, 3, len(getHistory(t, "?from="+now.AddDate(0, 0, -1).Format(`2006-01-02`))))
+	assert.Equal(t, 0, len(getHistory(t, "?to="+now.AddDate(0, 0, -1).Format(`20060102`))))
+	assert.Equal(t, 0, len(getHistory(t, "?from="+now.AddDate(0, 0, 1).Format(`02.01.2006`))))
+
+	for _, query := range []string{"?task_id=abc", "?from=завтра", "?to=20241345", "?from=" + today + "&to=" + now.AddDate(0, 0, -1).Format(`20060102`)} {
+		body, err := requestJSON("api/history"+query, nil, http.MethodGet)
+		assert.NoError(t, err)
+		var resp map[string]any
+		assert.NoError(t, json.Unmarshal(body, &resp))
+		assert.NotEmpty(t, resp["error"], "Ожидается ошибка для запроса %s", query)
+
+		res, err := http.Get(getURL("api/history" + query))
+		if assert.NoError(t, err) {
+			res.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		}
+	}
+
+	// Окончательное удаление задачи не стирает её историю.
+	ret, err = postJSON("api/trash/purge?id="+once, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	assert.Equal(t, 1, len(getHistory(t, "?task_id="+once)))
+
+	for _, table := range []string{"scheduler", "completions"} {
+		_, err := db.Exec("DELETE FROM " + table)
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func nextDateBody(t *testing.T, date, repeat string) string {
+	urlPath := fmt.Sprintf("api/nextdate?now=20240126&date=%s&repeat=%s",
+		url.QueryEscape(date), url.QueryEscape(repeat))
+	body, err := getBody(urlPath)
+	assert.NoError(t, err)
+	return strings.TrimSpace(string(body))
+}
+
+func TestHolidays(t *testing.T) {
+	tbl := []nextDate{
+		{"20240126", "b 1", "20240129"},
+		{"20240126", "b 3", "20240131"},
+		{"20240101", "bm 1", "20240201"},
+		{"20240101", "bm -1", "20240131"},
+		{"20240101", "bm 2,-2", "20240130"},
+		{"20240126", "b 0", ""},
+		{"20240126", "bm 30", ""},
+	}
+	for _, v := range tbl {
+		next := nextDateBody(t, v.date, v.repeat)
+		if len(v.want) == 0 {
+			assert.NotRegexp(t, `^\d{8}$`, next, `{%q, %q}`, v.date, v.repeat)
+			continue
+		}
+		assert.Equal(t, v.want, next, `{%q, %q, %q}`, v.date, v.repeat, v.want)
+	}
+
+	m, err := postJSON("api/holidays/import", map[string]any{
+		"holidays": []map[string]string{
+			{"date": "20240129", "name": "Тестовый праздник"},
+			{"date": "20240201", "name": "Ещё один праздник"},
+			{"date": "20240131", "name": ""},
+		},
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), m["imported"])
+
+	body, err := requestJSON("api/holidays", nil, http.MethodGet)
+	assert.NoError(t, err)
+	var list map[string][]map[string]string
+	assert.NoError(t, json.Unmarshal(body, &list))
+	dates := []string{}
+	for _, h := range list["holidays"] {
+		dates = append(dates, h["date"])
+	}
+	assert.Subset(t, dates, []string{"20240129", "20240131", "20240201"})
+
+	assert.Equal(t, "20240130", nextDateBody(t, "20240126", "b 1"))
+	assert.Equal(t, "20240202", nextDateBody(t, "20240101", "bm 1"))
+	assert.Equal(t, "20240130", nextDateBody(t, "20240101", "bm -1"))
+
+	m, err = postJSON("api/holidays/import", map[string]any{
+		"holidays": []map[string]string{{"date": "2024-13-29"}},
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m["error"])
+
+	// Даты праздников принимаются и возвращаются в тех же форматах, что и даты задач.
+	body, err = requestJSON("api/holidays?date_format=iso", nil, http.MethodGet)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(body, &list))
+	dates = []string{}
+	for _, h := range list["holidays"] {
+		dates = append(dates, h["date"])
+	}
+	assert.Subset(t, dates, []string{"2024-01-29", "2024-01-31", "2024-02-01"})
+
+	for _, date := range []string{"20240129", "2024-01-31", "01.02.2024"} {
+		m, err = postJSON("api/holidays?date="+date, nil, http.MethodDelete)
+		assert.NoError(t, err)
+		assert.Empty(t, m)
+	}
+	m, err = postJSON("api/holidays?date=20240129", nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m["error"])
+	assert.Equal(t, "20240129", nextDateBody(t, "20240126", "b 1"))
+}
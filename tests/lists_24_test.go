@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addList(t *testing.T, name string) string {
+	ret, err := postJSON("api/lists", map[string]any{"name": name}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotNil(t, ret["id"], "Не возвращён id для списка %q: %v", name, ret["error"])
+	return fmt.Sprint(ret["id"])
+}
+
+func addListTask(t *testing.T, date, title, listID string) string {
+	ret, err := postJSON("api/task", map[string]any{
+		"date":    date,
+		"title":   title,
+		"list_id": listID,
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotNil(t, ret["id"], "Не возвращён id для задачи %q: %v", title, ret["error"])
+	return fmt.Sprint(ret["id"])
+}
+
+func listTitles(t *testing.T, listID string) []string {
+	body, err := requestJSON("api/tasks?list_id="+listID, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var resp struct {
+		Tasks []struct {
+			Title  string `json:"title"`
+			ListID string `json:"list_id"`
+		} `json:"tasks"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	titles := []string{}
+	for _, task := range resp.Tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+func TestLists(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	for _, table := range []string{"scheduler", "lists"} {
+		_, err := db.Exec("DELETE FROM " + table)
+		assert.NoError(t, err)
+	}
+
+	date := time.Now().AddDate(0, 0, 1).Format(`20060102`)
+	personal := addList(t, "Personal")
+	release := addList(t, "Release 2.0")
+
+	addListTask(t, date, "Купить билеты", personal)
+	id := addListTask(t, date, "Собрать релиз", release)
+	addListTask(t, date, "Написать changelog", release)
+	addListTask(t, date, "Разобрать почту", "")
+
+	assert.Equal(t, []string{"Купить билеты"}, listTitles(t, personal))
+	assert.Equal(t, []string{"Собрать релиз", "Написать changelog"}, listTitles(t, release))
+	assert.Equal(t, []string{"Разобрать почту"}, listTitles(t, "0"))
+
+	ret, err := postJSON("api/task", map[string]any{
+		"date":    date,
+		"title":   "Задача в несуществующем списке",
+		"list_id": "100500",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// Обновление задачи без list_id оставляет её в прежнем списке.
+	ret, err = postJSON("api/task", map[string]any{
+		"id":    id,
+		"date":  date,
+		"title": "Собрать релиз 2.0",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	var stored Task
+	assert.NoError(t, db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id))
+	assert.Equal(t, release, fmt.Sprint(stored.ListID))
+
+	ret, err = postJSON("api/lists", map[string]any{"id": personal, "name": "Личное"}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	body, err := requestJSON("api/lists", nil, http.MethodGet)
+	assert.NoError(t, err)
+	var lists struct {
+		Lists []map[string]string `json:"lists"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &lists))
+	assert.Equal(t, []map[string]string{
+		{"id": release, "name": "Release 2.0"},
+		{"id": personal, "name": "Личное"},
+	}, lists.Lists)
+
+	// По умолчанию задачи удаляемого списка переносятся во «Входящие».
+	ret, err = postJSON("api/lists?id="+personal, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	assert.Equal(t, []string{"Купить билеты", "Разобрать почту"}, listTitles(t, "0"))
+
+	// При каскадном удалении задачи списка перемещаются в корзину.
+	ret, err = postJSON("api/lists?id="+release+"&mode=cascade", nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	var active int
+	assert.NoError(t, db.Get(&active, `SELECT count(*) FROM scheduler WHERE deleted_at = ''`))
+	assert.Equal(t, 2, active)
+
+	ret, err = postJSON("api/lists?id="+release, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	_, err = db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type occurrences struct {
+	date   string
+	repeat string
+	count  string
+	want   []string
+}
+
+func TestOccurrences(t *testing.T) {
+	tbl := []occurrences{
+		{"20240126", "", "", nil},
+		{"20240126", "ooops", "", nil},
+		{"20240126", "d 7", "abc", nil},
+		{"20240113", "d 7", "3", []string{"20240127", "20240203", "20240210"}},
+		{"20240229", "y", "2", []string{"20250301", "20260301"}},
+		{"20240127", "m -1", "3", []string{"20240131", "20240229", "20240331"}},
+		{"20240125", "w 1,3", "4", []string{"20240129", "20240131", "20240205", "20240207"}},
+		{"20240120", "d 1", "", []string{"20240127", "20240128", "20240129", "20240130", "20240131"}},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/occurrences?now=20240126&date=%s&repeat=%s&count=%s",
+			url.QueryEscape(v.date), url.QueryEscape(v.repeat), url.QueryEscape(v.count))
+		body, err := getBody(urlPath)
+		assert.NoError(t, err)
+
+		if v.want == nil {
+			var m map[string]any
+			err = json.Unmarshal(body, &m)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, m["error"], "Ожидается ошибка для %v", v)
+			continue
+		}
+		var dates []string
+		err = json.Unmarshal(body, &dates)
+		assert.NoError(t, err)
+		assert.Equal(t, v.want, dates, "%v", v)
+	}
+
+	body, err := getBody("api/occurrences?now=20240126&date=20240126&repeat=d+1&count=1000")
+	assert.NoError(t, err)
+	var dates []string
+	assert.NoError(t, json.Unmarshal(body, &dates))
+	assert.Equal(t, 50, len(dates))
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addPriorityTask(t *testing.T, date, title string, priority int) string {
+	ret, err := postJSON("api/task", map[string]any{
+		"date":     date,
+		"title":    title,
+		"priority": priority,
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotNil(t, ret["id"], "Не возвращён id для задачи %q: %v", title, ret["error"])
+	return fmt.Sprint(ret["id"])
+}
+
+func taskTitles(t *testing.T, query string) []string {
+	body, err := requestJSON("api/tasks"+query, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var resp struct {
+		Tasks []struct {
+			Title    string `json:"title"`
+			Priority string `json:"priority"`
+		} `json:"tasks"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	titles := []string{}
+	for _, task := range resp.Tasks {
+		titles = append(titles, task.Title+":"+task.Priority)
+	}
+	return titles
+}
+
+func TestPriority(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+
+	now := time.Now()
+	today := now.Format(`20060102`)
+	tomorrow := now.AddDate(0, 0, 1).Format(`20060102`)
+
+	addPriorityTask(t, today, "Полить цветы", 0)
+	addPriorityTask(t, today, "Сдать отчёт", 3)
+	addPriorityTask(t, tomorrow, "Позвонить", 1)
+	id := addPriorityTask(t, tomorrow, "Заплатить", 3)
+
+	assert.Equal(t, []string{"Сдать отчёт:3", "Полить цветы:0", "Заплатить:3", "Позвонить:1"}, taskTitles(t, "?sort=date"))
+	assert.Equal(t, []string{"Сдать отчёт:3", "Заплатить:3", "Позвонить:1", "Полить цветы:0"}, taskTitles(t, "?sort=priority"))
+
+	var stored Task
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stored.Priority)
+
+	ret, err := postJSON("api/task", map[string]any{
+		"id":       id,
+		"date":     tomorrow,
+		"title":    "Заплатить",
+		"priority": 2,
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+
+	body, err := requestJSON("api/task?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var task map[string]any
+	assert.NoError(t, json.Unmarshal(body, &task))
+	assert.Equal(t, "2", task["priority"])
+
+	// Приоритет принимается и строкой, как ID задачи.
+	ret, err = postJSON("api/task", map[string]any{
+		"id":       id,
+		"date":     tomorrow,
+		"title":    "Заплатить",
+		"priority": "1",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stored.Priority)
+
+	for _, priority := range []int{-1, 4} {
+		ret, err := postJSON("api/task", map[string]any{
+			"date":     today,
+			"title":    "Неверный приоритет",
+			"priority": priority,
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ret["error"], "priority %d", priority)
+	}
+
+	resp, err := http.Get(getURL("api/tasks?sort=title"))
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+
+	_, err = db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+}
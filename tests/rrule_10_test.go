@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRRule(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+
+	tbl := []struct {
+		rrule  string
+		repeat string
+		back   string
+	}{
+		{"FREQ=WEEKLY;BYDAY=MO,WE", "w 1,3", "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"RRULE:FREQ=DAILY;INTERVAL=5", "d 5", "FREQ=DAILY;INTERVAL=5"},
+		{"FREQ=WEEKLY;INTERVAL=2", "d 14", "FREQ=DAILY;INTERVAL=14"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15,-1", "m 1,15,-1", "FREQ=MONTHLY;BYMONTHDAY=1,15,-1"},
+		{"FREQ=YEARLY;COUNT=3", "y count 3", "FREQ=YEARLY;COUNT=3"},
+		{"FREQ=MONTHLY;BYDAY=2TU", "wm 2 2", "FREQ=MONTHLY;BYDAY=2TU"},
+		{"FREQ=YEARLY;BYMONTH=12,3;BYMONTHDAY=25", "y 0325,1225", "FREQ=YEARLY;BYMONTH=3,12;BYMONTHDAY=25"},
+		{"FREQ=MONTHLY;BYDAY=-1FR,1FR;BYMONTH=3", "wm 1,-1 5 3", "FREQ=MONTHLY;BYDAY=1FR,-1FR;BYMONTH=3"},
+	}
+	for _, v := range tbl {
+		id := addTask(t, task{
+			date:   now.Format(`20060102`),
+			title:  "Синхронизация с календарём",
+			repeat: v.rrule,
+		})
+
+		var stored Task
+		err := db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+		assert.NoError(t, err)
+		assert.Equal(t, v.repeat, stored.Repeat)
+
+		body, err := requestJSON("api/task?id="+id, nil, http.MethodGet)
+		assert.NoError(t, err)
+		var m map[string]string
+		err = json.Unmarshal(body, &m)
+		assert.NoError(t, err)
+		assert.Equal(t, v.repeat, m["repeat"])
+		assert.Equal(t, v.back, m["rrule"])
+	}
+
+	for _, rrule := range []string{
+		"FREQ=SECONDLY",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYDAY=1MO,2TU",
+		"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29",
+		"INTERVAL=2",
+	} {
+		m, err := postJSON("api/task", map[string]any{
+			"date":   now.Format(`20060102`),
+			"title":  "Неподдерживаемое правило",
+			"repeat": rrule,
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		e, ok := m["error"]
+		assert.False(t, !ok || len(fmt.Sprint(e)) == 0, "Ожидается ошибка для правила %q", rrule)
+	}
+
+	// Задача на 29 февраля по правилу y переходит на 1 марта, а RRULE пропустил бы невисокосные годы.
+	id := addTask(t, task{
+		date:   "20280229",
+		title:  "Високосный день",
+		repeat: "y",
+	})
+	body, err := requestJSON("api/task?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.Equal(t, "y", m["repeat"])
+	assert.Empty(t, m["rrule"])
+
+	for date, want := range map[string]string{"20280229": "", "20280301": "FREQ=YEARLY"} {
+		body, err := requestJSON("api/repeat/validate?repeat=y&date="+date, nil, http.MethodGet)
+		assert.NoError(t, err)
+		var resp map[string]any
+		assert.NoError(t, json.Unmarshal(body, &resp))
+		assert.Equal(t, true, resp["valid"])
+		if want == "" {
+			assert.Nil(t, resp["rrule"], "Для даты %s", date)
+		} else {
+			assert.Equal(t, want, resp["rrule"], "Для даты %s", date)
+		}
+	}
+
+	// И наоборот, FREQ=YEARLY для задачи на 29 февраля нельзя точно заменить правилом y.
+	for _, rrule := range []string{"FREQ=YEARLY", "RRULE:FREQ=YEARLY;COUNT=3"} {
+		m, err := postJSON("api/task", map[string]any{
+			"date":   "20280229",
+			"title":  "Високосный день",
+			"repeat": rrule,
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, m["error"], "Ожидается ошибка для правила %q", rrule)
+
+		body, err := getBody("api/repeat/validate?date=20280229&repeat=" + url.QueryEscape(rrule))
+		assert.NoError(t, err)
+		var resp validateResp
+		assert.NoError(t, json.Unmarshal(body, &resp))
+		assert.False(t, resp.Valid, rrule)
+		if assert.NotNil(t, resp.Details, rrule) {
+			assert.Equal(t, "unsupported", resp.Details.Code, rrule)
+			assert.Equal(t, "FREQ=YEARLY", resp.Details.Token, rrule)
+		}
+	}
+	body, err = getBody("api/repeat/validate?date=20280301&repeat=FREQ%3DYEARLY")
+	assert.NoError(t, err)
+	var resp validateResp
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	assert.True(t, resp.Valid)
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeries(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	id := addTask(t, task{
+		date:   now.Format(`20060102`),
+		title:  "Пройти курс массажа",
+		repeat: "d 3 count 2",
+	})
+
+	ret, err := postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	var stored Task
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, 3).Format(`20060102`), stored.Date)
+	assert.Equal(t, "d 3 count 1", stored.Repeat)
+
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	notFoundTask(t, id)
+
+	until := now.AddDate(0, 0, 4).Format(`20060102`)
+	id = addTask(t, task{
+		date:   now.Format(`20060102`),
+		title:  "Принимать витамины",
+		repeat: "d 3 until " + until,
+	})
+
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, 3).Format(`20060102`), stored.Date)
+
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	notFoundTask(t, id)
+
+	for _, repeat := range []string{"d 3 count 0", "d 3 until 2024", "d 3 count", "y until 20200101"} {
+		m, err := postJSON("api/task", map[string]any{
+			"date":   now.Format(`20060102`),
+			"title":  "Ошибочное правило",
+			"repeat": repeat,
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, m["error"], "Ожидается ошибка для правила %q", repeat)
+	}
+}
+
+func TestSeriesAfterDone(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	id := addTask(t, task{
+		date:   now.AddDate(0, 0, 2).Format(`20060102`),
+		title:  "Полить цветы",
+		repeat: "d 5 after",
+	})
+
+	// Выполнение раньше срока: следующая дата отсчитывается от сегодняшнего дня.
+	ret, err := postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	var stored Task
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, 5).Format(`20060102`), stored.Date)
+	assert.Equal(t, "d 5 after", stored.Repeat)
+
+	id = addTask(t, task{
+		date:   now.AddDate(0, 0, 2).Format(`20060102`),
+		title:  "Полить цветы по расписанию",
+		repeat: "d 5",
+	})
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, 7).Format(`20060102`), stored.Date)
+
+	m, err := postJSON("api/task", map[string]any{
+		"date":   now.Format(`20060102`),
+		"title":  "Ошибочное правило",
+		"repeat": "d 5 after after",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m["error"])
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubDaily(t *testing.T) {
+	tbl := []struct {
+		date   string
+		time   string
+		repeat string
+		want   string
+	}{
+		{"20240126", "08:00", "h 4", "20240126 12:00"},
+		{"20240126", "08:00", "min 90", "20240126 11:00"},
+		{"20240120", "08:00", "h 5", "20240126 14:00"},
+		{"20240126", "22:00", "h 3", "20240127 01:00"},
+		{"20240126", "09:00", "d 1", "20240127 09:00"},
+		{"20240126", "09:00", "h 3 until 20240126", "20240126 12:00"},
+		{"20240126", "", "h 4", ""},
+		{"20240126", "08:00", "h 25", ""},
+		{"20240126", "08:00", "min 0", ""},
+		{"20240126", "08:00", "h 4 until 20240125", ""},
+	}
+	for _, v := range tbl {
+		urlPath := fmt.Sprintf("api/nextdate?now=%s&date=%s&time=%s&repeat=%s",
+			url.QueryEscape("20240126 10:00"), v.date, url.QueryEscape(v.time), url.QueryEscape(v.repeat))
+		body, err := getBody(urlPath)
+		assert.NoError(t, err)
+		next := strings.TrimSpace(string(body))
+		if len(v.want) == 0 {
+			assert.NotRegexp(t, `^\d{8} \d\d:\d\d$`, next, "%v", v)
+			continue
+		}
+		assert.Equal(t, v.want, next, "%v", v)
+	}
+
+	body, err := getBody("api/occurrences?now=" + url.QueryEscape("20240126 21:30") +
+		"&date=20240126&time=22:00&repeat=h+1&count=3")
+	assert.NoError(t, err)
+	var dates []string
+	assert.NoError(t, json.Unmarshal(body, &dates))
+	assert.Equal(t, []string{"20240126 23:00", "20240127 00:00", "20240127 01:00"}, dates)
+}
+
+func TestSubDailyDone(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	id := addTimedTask(t, now.Format(`20060102`), "00:00", "Принять лекарство", "h 2")
+
+	ret, err := postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	passed := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute
+	want := midnight.Add((passed/(2*time.Hour) + 1) * 2 * time.Hour)
+
+	var stored Task
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, want.Format(`20060102`), stored.Date)
+	assert.Equal(t, want.Format(`15:04`), stored.Time)
+
+	m, err := postJSON("api/task", map[string]any{
+		"date":   now.Format(`20060102`),
+		"title":  "Без времени",
+		"repeat": "h 2",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m["error"])
+}
+
+func TestSubDailySkip(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(`20060102`)
+	id := addTimedTask(t, tomorrow, "08:00", "Проветрить комнату", "h 4")
+
+	// Пропуск повторения по правилу h переносит задачу на следующее время того же дня.
+	for _, want := range []string{"12:00", "16:00"} {
+		ret, err := postJSON("api/task/skip?id="+id, nil, http.MethodPost)
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+
+		var stored Task
+		err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+		assert.NoError(t, err)
+		assert.Equal(t, tomorrow, stored.Date)
+		assert.Equal(t, want, stored.Time)
+	}
+
+	// День целиком в даты-исключения не попадает.
+	body, err := requestJSON("api/task/exceptions?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var m map[string][]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.Empty(t, m["dates"])
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type subtask struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Done     bool   `json:"done"`
+	Position int    `json:"position"`
+}
+
+type progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+func getSubtasks(t *testing.T, taskID string) []subtask {
+	body, err := requestJSON("api/subtasks?task_id="+taskID, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var resp struct {
+		Subtasks []subtask `json:"subtasks"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	return resp.Subtasks
+}
+
+func taskProgress(t *testing.T, taskID string) *progress {
+	body, err := requestJSON("api/tasks", nil, http.MethodGet)
+	assert.NoError(t, err)
+	var resp struct {
+		Tasks []struct {
+			ID       string    `json:"id"`
+			Progress *progress `json:"progress"`
+		} `json:"tasks"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	for _, task := range resp.Tasks {
+		if task.ID == taskID {
+			return task.Progress
+		}
+	}
+	t.Errorf("Задача с ID %s не найдена в списке задач", taskID)
+	return nil
+}
+
+func TestSubtasks(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+
+	now := time.Now()
+	id := addTask(t, task{date: now.Format(`20060102`), title: "Подготовить релиз", repeat: "d 7"})
+	assert.Nil(t, taskProgress(t, id))
+
+	ids := []string{}
+	for _, title := range []string{"Собрать сборку", "Прогнать тесты", "Написать changelog"} {
+		ret, err := postJSON("api/subtasks", map[string]any{"task_id": id, "title": title}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotNil(t, ret["id"], "Не возвращён id для пункта %q: %v", title, ret["error"])
+		ids = append(ids, fmt.Sprint(ret["id"]))
+	}
+
+	ret, err := postJSON("api/subtasks", map[string]any{"id": ids[0], "title": "Собрать сборку", "done": true}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	assert.Equal(t, &progress{Done: 1, Total: 3}, taskProgress(t, id))
+
+	ret, err = postJSON("api/subtasks/order", map[string]any{
+		"task_id": id,
+		"ids":     []string{ids[2], ids[0], ids[1]},
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	list := getSubtasks(t, id)
+	if assert.Len(t, list, 3) {
+		assert.Equal(t, "Написать changelog", list[0].Title)
+		assert.Equal(t, 1, list[0].Position)
+		assert.True(t, list[1].Done)
+	}
+
+	ret, err = postJSON("api/subtasks/order", map[string]any{"task_id": id, "ids": []string{ids[0]}}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// После выполнения повторяющейся задачи чек-лист сбрасывается.
+	ret, err = postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	assert.Equal(t, &progress{Done: 0, Total: 3}, taskProgress(t, id))
+
+	ret, err = postJSON("api/subtasks?id="+ids[1], nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	assert.Len(t, getSubtasks(t, id), 2)
+
+	// Окончательное удаление задачи удаляет и её чек-лист.
+	ret, err = postJSON("api/task?id="+id, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	ret, err = postJSON("api/trash/purge?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	var left int
+	assert.NoError(t, db.Get(&left, `SELECT count(*) FROM subtasks WHERE task_id = ?`, id))
+	assert.Equal(t, 0, left)
+}
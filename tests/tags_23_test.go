@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addTaggedTask(t *testing.T, date, title string, tags []string) string {
+	ret, err := postJSON("api/task", map[string]any{
+		"date":  date,
+		"title": title,
+		"tags":  tags,
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotNil(t, ret["id"], "Не возвращён id для задачи %q: %v", title, ret["error"])
+	return fmt.Sprint(ret["id"])
+}
+
+func taggedTitles(t *testing.T, query string) []string {
+	body, err := requestJSON("api/tasks"+query, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var resp struct {
+		Tasks []struct {
+			Title string   `json:"title"`
+			Tags  []string `json:"tags"`
+		} `json:"tasks"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	titles := []string{}
+	for _, task := range resp.Tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+func TestTags(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	for _, table := range []string{"scheduler", "task_tags", "tags"} {
+		_, err := db.Exec("DELETE FROM " + table)
+		assert.NoError(t, err)
+	}
+
+	date := time.Now().AddDate(0, 0, 1).Format(`20060102`)
+	addTaggedTask(t, date, "Купить хлеб", []string{"#errands", "home"})
+	id := addTaggedTask(t, date, "Отчёт", []string{"work"})
+	addTaggedTask(t, date, "Починить кран", []string{"home"})
+	addTaggedTask(t, date, "Без меток", nil)
+
+	body, err := requestJSON("api/tags", nil, http.MethodGet)
+	assert.NoError(t, err)
+	var tags struct {
+		Tags []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"tags"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &tags))
+	names := []string{}
+	for _, tag := range tags.Tags {
+		names = append(names, tag.Name)
+	}
+	assert.Equal(t, []string{"errands", "home", "work"}, names)
+
+	assert.Equal(t, []string{"Купить хлеб", "Починить кран"}, taggedTitles(t, "?tags=home"))
+	assert.Equal(t, []string{"Купить хлеб", "Отчёт"}, taggedTitles(t, "?tags=errands,work"))
+	assert.Equal(t, []string{"Купить хлеб"}, taggedTitles(t, "?tags=home,errands&match=all"))
+	assert.Equal(t, []string{}, taggedTitles(t, "?tags=home,work&match=all"))
+
+	// Изменение задачи без поля tags не снимает метки, пустой список снимает.
+	ret, err := postJSON("api/task", map[string]any{
+		"id":    id,
+		"date":  date,
+		"title": "Отчёт за месяц",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	assert.Equal(t, []string{"Купить хлеб", "Отчёт за месяц"}, taggedTitles(t, "?tags=errands,work"))
+
+	ret, err = postJSON("api/task", map[string]any{
+		"id":    id,
+		"date":  date,
+		"title": "Отчёт за месяц",
+		"tags":  []string{"home"},
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	body, err = requestJSON("api/task?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var task struct {
+		Tags []string `json:"tags"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &task))
+	assert.Equal(t, []string{"home"}, task.Tags)
+
+	// Метку можно переименовать, а повторное название отклоняется.
+	ret, err = postJSON("api/tags", map[string]any{"name": "Garden"}, http.MethodPost)
+	assert.NoError(t, err)
+	gardenID := fmt.Sprint(ret["id"])
+	ret, err = postJSON("api/tags", map[string]any{"name": "garden"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+	ret, err = postJSON("api/tags", map[string]any{"id": gardenID, "name": "yard"}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+
+	// Окончательное удаление задачи и удаление метки убирают их связи.
+	ret, err = postJSON("api/task?id="+id, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	ret, err = postJSON("api/trash/purge?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Nil(t, ret["error"])
+	var links int
+	assert.NoError(t, db.Get(&links, `SELECT count(*) FROM task_tags WHERE task_id = ?`, id))
+	assert.Equal(t, 0, links)
+
+	for _, tag := range tags.Tags {
+		if tag.Name == "home" {
+			ret, err = postJSON("api/tags?id="+tag.ID, nil, http.MethodDelete)
+			assert.NoError(t, err)
+			assert.Nil(t, ret["error"])
+		}
+	}
+	assert.Equal(t, []string{"Купить хлеб"}, taggedTitles(t, "?tags=home,errands&match=any"))
+
+	for _, table := range []string{"scheduler", "task_tags", "tags"} {
+		_, err := db.Exec("DELETE FROM " + table)
+		assert.NoError(t, err)
+	}
+}
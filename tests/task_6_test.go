@@ -74,7 +74,7 @@ func TestEditTask(t *testing.T) {
 		{"7645346343", task{"20240129", "Тест", "", ""}},
 		{id, task{"20240129", "", "", ""}},
 		{id, task{"20240192", "Qwerty", "", ""}},
-		{id, task{"28.01.2024", "Заголовок", "", ""}},
+		{id, task{"28.13.2024", "Заголовок", "", ""}},
 		{id, task{"20240212", "Заголовок", "", "ooops"}},
 	}
 	for _, v := range tbl {
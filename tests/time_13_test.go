@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addTimedTask(t *testing.T, date, tm, title, repeat string) string {
+	ret, err := postJSON("api/task", map[string]any{
+		"date":   date,
+		"time":   tm,
+		"title":  title,
+		"repeat": repeat,
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotNil(t, ret["id"], "Не возвращён id для задачи %q", title)
+	return fmt.Sprint(ret["id"])
+}
+
+func TestTaskTime(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+
+	today := time.Now().Format(`20060102`)
+	addTimedTask(t, today, "16:00", "Созвон", "")
+	addTimedTask(t, today, "9:30", "Зарядка", "")
+	addTimedTask(t, today, "", "Весь день", "")
+	id := addTimedTask(t, today, "12:15", "Обед", "d 2")
+
+	tasks := getTasks(t, "")
+	assert.Equal(t, 4, len(tasks))
+	titles := []string{}
+	for _, task := range tasks {
+		titles = append(titles, task["title"])
+	}
+	assert.Equal(t, []string{"Весь день", "Зарядка", "Обед", "Созвон"}, titles)
+	assert.Equal(t, "09:30", tasks[1]["time"])
+
+	ret, err := postJSON("api/task/done?id="+id, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	var stored Task
+	err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Now().AddDate(0, 0, 2).Format(`20060102`), stored.Date)
+	assert.Equal(t, "12:15", stored.Time)
+
+	for _, tm := range []string{"25:00", "12:60", "noon", "1215"} {
+		m, err := postJSON("api/task", map[string]any{
+			"date":  today,
+			"time":  tm,
+			"title": "Ошибочное время",
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, m["error"], "Ожидается ошибка для времени %q", tm)
+	}
+}
@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimezone(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	for _, zone := range []string{"Pacific/Kiritimati", "Pacific/Pago_Pago"} {
+		loc, err := time.LoadLocation(zone)
+		assert.NoError(t, err)
+
+		m, err := postJSON("api/task?tz="+zone, map[string]any{
+			"date":  "",
+			"title": "Задача в поясе " + zone,
+		}, http.MethodPost)
+		assert.NoError(t, err)
+		id := fmt.Sprint(m["id"])
+
+		var stored Task
+		err = db.Get(&stored, `SELECT * FROM scheduler WHERE id=?`, id)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Now().In(loc).Format(`20060102`), stored.Date, zone)
+
+		body, err := getBody("api/nextdate?tz=" + zone + "&date=20240101&repeat=d+1")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Now().In(loc).AddDate(0, 0, 1).Format(`20060102`),
+			strings.TrimSpace(string(body)), zone)
+	}
+
+	m, err := postJSON("api/task?tz=Mars/Olympus", map[string]any{
+		"date":  "",
+		"title": "Задача в несуществующем поясе",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m["error"])
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func trashedIDs(t *testing.T) []string {
+	body, err := requestJSON("api/trash", nil, http.MethodGet)
+	assert.NoError(t, err)
+	var resp struct {
+		Tasks []struct {
+			ID        string `json:"id"`
+			DeletedAt string `json:"deleted_at"`
+		} `json:"tasks"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	ids := []string{}
+	for _, task := range resp.Tasks {
+		assert.NotEmpty(t, task.DeletedAt)
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func TestTrash(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	_, err := db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+
+	date := time.Now().Format(`20060102`)
+	deleted := addTask(t, task{date: date, title: "Удалённая по ошибке", repeat: "d 7"})
+	done := addTask(t, task{date: date, title: "Выполненная"})
+	addTask(t, task{date: date, title: "Обычная"})
+
+	ret, err := postJSON("api/subtasks", map[string]any{"task_id": deleted, "title": "Пункт"}, http.MethodPost)
+	assert.NoError(t, err)
+	subtask := fmt.Sprint(ret["id"])
+
+	ret, err = postJSON("api/task?id="+deleted, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	ret, err = postJSON("api/task/done?id="+done, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+
+	// Задачи в корзине не видны в обычных запросах, но остаются в БД.
+	notFoundTask(t, deleted)
+	assert.Equal(t, 1, len(getTasks(t, "")))
+	total, err := count(db)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.ElementsMatch(t, []string{deleted, done}, trashedIDs(t))
+
+	ret, err = postJSON("api/task", map[string]any{
+		"id":    deleted,
+		"date":  date,
+		"title": "Изменение задачи в корзине",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// Чек-лист задачи в корзине изменить нельзя.
+	ret, err = postJSON("api/subtasks", map[string]any{"id": subtask, "title": "Изменённый пункт"}, http.MethodPut)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+	ret, err = postJSON("api/subtasks?id="+subtask, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// Восстановленная задача возвращается в список задач.
+	ret, err = postJSON("api/trash/restore?id="+deleted, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	body, err := requestJSON("api/task?id="+deleted, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var restored struct {
+		Repeat string `json:"repeat"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &restored))
+	assert.Equal(t, "d 7", restored.Repeat)
+	assert.Equal(t, []string{done}, trashedIDs(t))
+
+	ret, err = postJSON("api/trash/restore?id="+deleted, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ret["error"])
+
+	// Окончательное удаление убирает задачу из БД.
+	ret, err = postJSON("api/trash/purge?id="+done, nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	total, err = count(db)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	ret, err = postJSON("api/task?id="+deleted, nil, http.MethodDelete)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	ret, err = postJSON("api/trash/purge", nil, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Empty(t, ret)
+	assert.Equal(t, []string{}, trashedIDs(t))
+	total, err = count(db)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	_, err = db.Exec("DELETE FROM scheduler")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseErrorDetails struct {
+	Code    string `json:"code"`
+	Token   string `json:"token"`
+	Pos     int    `json:"pos"`
+	Message string `json:"message"`
+	Hint    string `json:"hint"`
+}
+
+type validateResp struct {
+	Valid   bool               `json:"valid"`
+	Repeat  string             `json:"repeat"`
+	Error   string             `json:"error"`
+	Details *parseErrorDetails `json:"details"`
+}
+
+func TestValidateRepeat(t *testing.T) {
+	tbl := []struct {
+		repeat string
+		code   string
+		token  string
+		pos    int
+	}{
+		{"", "empty", "", 0},
+		{"x 3", "unknown_rule", "x", 0},
+		{"d", "missing_value", "", 1},
+		{"d abc", "not_a_number", "abc", 2},
+		{"d 500", "out_of_range", "500", 2},
+		{"d 7 3", "extra_argument", "3", 4},
+		{"m 1,40", "out_of_range", "40", 4},
+		{"wm 2 2,9", "out_of_range", "9", 7},
+		{"m 31 2", "never_occurs", "31", 2},
+		{"wm 5 1 2", "never_occurs", "5", 3},
+		{"y 0230", "invalid_date", "0230", 2},
+		{"d 7 until 2025", "invalid_date", "2025", 10},
+		{"d 7 count 0", "out_of_range", "0", 10},
+		{"d 7 until", "missing_value", "", 9},
+		{"FREQ=WEEKLY;BYDAY=XX", "invalid_value", "BYDAY=XX", 12},
+		{"FREQ=SECONDLY", "unsupported", "FREQ=SECONDLY", 0},
+	}
+	for _, v := range tbl {
+		body, err := getBody("api/repeat/validate?repeat=" + url.QueryEscape(v.repeat))
+		assert.NoError(t, err)
+		var resp validateResp
+		assert.NoError(t, json.Unmarshal(body, &resp))
+		assert.False(t, resp.Valid, v.repeat)
+		assert.NotEmpty(t, resp.Error, v.repeat)
+		if !assert.NotNil(t, resp.Details, v.repeat) {
+			continue
+		}
+		assert.Equal(t, v.code, resp.Details.Code, v.repeat)
+		assert.Equal(t, v.token, resp.Details.Token, v.repeat)
+		assert.Equal(t, v.pos, resp.Details.Pos, v.repeat)
+		assert.NotEmpty(t, resp.Details.Hint, v.repeat)
+	}
+
+	body, err := requestJSON("api/repeat/validate", map[string]any{"repeat": "m 15,1"}, http.MethodPost)
+	assert.NoError(t, err)
+	var resp validateResp
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	assert.True(t, resp.Valid)
+	assert.Equal(t, "m 1,15", resp.Repeat)
+	assert.Nil(t, resp.Details)
+}
+
+func TestTaskRepeatDetails(t *testing.T) {
+	date := time.Now().AddDate(0, 0, 1).Format(`20060102`)
+	body, err := requestJSON("api/task", map[string]any{
+		"date":   date,
+		"title":  "Ошибка в правиле",
+		"repeat": "w 1,8",
+	}, http.MethodPost)
+	assert.NoError(t, err)
+	var resp struct {
+		Error   string             `json:"error"`
+		Details *parseErrorDetails `json:"details"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	assert.NotEmpty(t, resp.Error)
+	if assert.NotNil(t, resp.Details) {
+		assert.Equal(t, "out_of_range", resp.Details.Code)
+		assert.Equal(t, "8", resp.Details.Token)
+		assert.Equal(t, 4, resp.Details.Pos)
+	}
+
+	id := addTask(t, task{date: date, title: "Проверка PUT"})
+	body, err = requestJSON("api/task", map[string]any{
+		"id":     id,
+		"date":   date,
+		"title":  "Проверка PUT",
+		"repeat": "d 7 count",
+	}, http.MethodPut)
+	assert.NoError(t, err)
+	resp.Details = nil
+	assert.NoError(t, json.Unmarshal(body, &resp))
+	if assert.NotNil(t, resp.Details) {
+		assert.Equal(t, "missing_value", resp.Details.Code)
+		assert.Equal(t, 9, resp.Details.Pos)
+	}
+}
@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekdayOfMonth(t *testing.T) {
+	tbl := []nextDate{
+		{"20240101", "wm 2 2", "20240213"},
+		{"20240101", "wm -1 5", "20240223"},
+		{"20240101", "wm 1,3 1", "20240205"},
+		{"20240101", "wm 5 4", "20240229"},
+		{"20240101", "wm 2 2 12", "20241210"},
+		{"20240101", "wm 5 1 2,3", "20250331"},
+		{"20240101", "wm 5 1 2", ""},
+		{"20240101", "wm 6 1", ""},
+		{"20240101", "wm 0 1", ""},
+		{"20240101", "wm 1 8", ""},
+		{"20240101", "wm 2", ""},
+	}
+	for _, v := range tbl {
+		next := nextDateBody(t, v.date, v.repeat)
+		if len(v.want) == 0 {
+			assert.NotRegexp(t, `^\d{8}$`, next, `{%q, %q}`, v.date, v.repeat)
+			continue
+		}
+		assert.Equal(t, v.want, next, `{%q, %q, %q}`, v.date, v.repeat, v.want)
+	}
+
+	id := addTask(t, task{
+		date:   time.Now().Format(`20060102`),
+		title:  "Планёрка команды",
+		repeat: "wm -1,2 2",
+	})
+	body, err := requestJSON("api/task?id="+id, nil, http.MethodGet)
+	assert.NoError(t, err)
+	var m map[string]string
+	assert.NoError(t, json.Unmarshal(body, &m))
+	assert.Equal(t, "wm 2,-1 2", m["repeat"])
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=2TU,-1TU", m["rrule"])
+}
@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYearlyDates(t *testing.T) {
+	tbl := []nextDate{
+		{"20240101", "y 0315,1225", "20240315"},
+		{"20240401", "y 1225,0315", "20241225"},
+		{"20240101", "y 0229", "20240229"},
+		{"20240301", "y 0229", "20250301"},
+		{"20240301", "y 0229 mar1", "20250301"},
+		{"20240301", "y 0229 feb28", "20250228"},
+		{"20250301", "y 0229 feb28", "20260228"},
+		{"20270301", "y 0229 feb28", "20280229"},
+		{"20240229", "y", "20250301"},
+		{"20240101", "y 1301", ""},
+		{"20240101", "y 0230", ""},
+		{"20240101", "y 315", ""},
+		{"20240101", "y feb28", ""},
+		{"20240101", "y 0101 jan1", ""},
+	}
+	for _, v := range tbl {
+		next := nextDateBody(t, v.date, v.repeat)
+		if len(v.want) == 0 {
+			assert.NotRegexp(t, `^\d{8}$`, next, `{%q, %q}`, v.date, v.repeat)
+			continue
+		}
+		assert.Equal(t, v.want, next, `{%q, %q, %q}`, v.date, v.repeat, v.want)
+	}
+}